@@ -0,0 +1,209 @@
+// Layer: Domain (business rules: ability score generation; no IO)
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const pointBuyBudget = 27
+
+var pointBuyCosts = map[int]int{8: 0, 9: 1, 10: 2, 11: 3, 12: 4, 13: 5, 14: 7, 15: 9}
+
+var abilityShortNames = []string{"str", "dex", "con", "int", "wis", "cha"}
+
+var abilityLongNames = []string{"strength", "dexterity", "constitution", "intelligence", "wisdom", "charisma"}
+
+/**
+*  abilityArray returns the scores in STR, DEX, CON, INT, WIS, CHA order
+**/
+func abilityArray(s AbilityScores) [6]int {
+	return [6]int{s.Strength, s.Dexterity, s.Constitution, s.Intelligence, s.Wisdom, s.Charisma}
+}
+
+/**
+*  abilitiesFromArray is the inverse of abilityArray
+**/
+func abilitiesFromArray(a [6]int) AbilityScores {
+	return AbilityScores{
+		Strength:     a[0],
+		Dexterity:    a[1],
+		Constitution: a[2],
+		Intelligence: a[3],
+		Wisdom:       a[4],
+		Charisma:     a[5],
+	}
+}
+
+/**
+*  addScores adds two score sets field by field (e.g. base + racial deltas)
+**/
+func addScores(a, b AbilityScores) AbilityScores {
+	x, y := abilityArray(a), abilityArray(b)
+	for i := range x {
+		x[i] += y[i]
+	}
+	return abilitiesFromArray(x)
+}
+
+/**
+*  subScores subtracts b from a field by field (e.g. final - racial deltas)
+**/
+func subScores(a, b AbilityScores) AbilityScores {
+	x, y := abilityArray(a), abilityArray(b)
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return abilitiesFromArray(x)
+}
+
+/**
+*  normalizeAbilityMethod maps a user supplied method to "", "standard", "pointbuy" or "roll"
+**/
+func normalizeAbilityMethod(m string) (string, error) {
+	switch strings.ReplaceAll(strings.ReplaceAll(trimLower(m), "-", ""), " ", "") {
+	case "":
+		return "", nil
+	case "standard", "standardarray", "array":
+		return "standard", nil
+	case "pointbuy", "points":
+		return "pointbuy", nil
+	case "roll", "rolled", "4d6":
+		return "roll", nil
+	default:
+		return "", fmt.Errorf("unknown ability method %q (use standard, pointbuy or roll)", m)
+	}
+}
+
+/**
+*  pointBuyCost returns the SRD point-buy cost of the scores, checking the 8–15 range
+**/
+func pointBuyCost(s AbilityScores) (int, error) {
+	total := 0
+	for i, v := range abilityArray(s) {
+		cost, ok := pointBuyCosts[v]
+		if !ok {
+			return 0, fmt.Errorf("point buy: %s %d is outside the 8-15 range", strings.ToUpper(abilityShortNames[i]), v)
+		}
+		total += cost
+	}
+	return total, nil
+}
+
+/**
+*  pointBuyScores fills unset scores with 8 and checks the 27-point budget; it returns the points left
+**/
+func pointBuyScores(s AbilityScores) (AbilityScores, int, error) {
+	arr := abilityArray(s)
+	for i := range arr {
+		if arr[i] == 0 {
+			arr[i] = 8
+		}
+	}
+	out := abilitiesFromArray(arr)
+	spent, err := pointBuyCost(out)
+	if err != nil {
+		return AbilityScores{}, 0, err
+	}
+	if spent > pointBuyBudget {
+		return AbilityScores{}, 0, fmt.Errorf("point buy: %d points spent, budget is %d", spent, pointBuyBudget)
+	}
+	return out, pointBuyBudget - spent, nil
+}
+
+/**
+*  abilityIndex returns the STR..CHA position of a short or long ability name
+**/
+func abilityIndex(name string) int {
+	n := trimLower(name)
+	for i := range abilityShortNames {
+		if n == abilityShortNames[i] || n == abilityLongNames[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+/**
+*  classPriority returns the ability order a class wants its best scores in
+**/
+func classPriority(class string) []string {
+	if c, ok := lookupClass(class); ok && len(c.Priority) == len(abilityShortNames) {
+		return c.Priority
+	}
+	return abilityShortNames
+}
+
+/**
+*  resolvePriority validates a user priority list (short or long names) and completes it in class order
+**/
+func resolvePriority(override []string, class string) ([]string, error) {
+	if len(override) == 0 {
+		return classPriority(class), nil
+	}
+	seen := map[int]bool{}
+	out := make([]string, 0, len(abilityShortNames))
+	for _, ab := range override {
+		idx := abilityIndex(ab)
+		if idx < 0 {
+			return nil, fmt.Errorf("unknown ability %q in priority (use str, dex, con, int, wis, cha)", ab)
+		}
+		if seen[idx] {
+			return nil, fmt.Errorf("ability %q listed twice in priority", ab)
+		}
+		seen[idx] = true
+		out = append(out, abilityShortNames[idx])
+	}
+	for _, ab := range classPriority(class) {
+		if idx := abilityIndex(ab); !seen[idx] {
+			seen[idx] = true
+			out = append(out, ab)
+		}
+	}
+	return out, nil
+}
+
+/**
+*  assignByPriority puts the highest value into the first ability of the priority list and so on
+**/
+func assignByPriority(values []int, priority []string) AbilityScores {
+	sorted := append([]int(nil), values...)
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+	var arr [6]int
+	for i, ab := range priority {
+		if i < len(sorted) {
+			if idx := abilityIndex(ab); idx >= 0 {
+				arr[idx] = sorted[i]
+			}
+		}
+	}
+	return abilitiesFromArray(arr)
+}
+
+/**
+*  rollAbilityScores rolls 4d6-drop-lowest six times and assigns the totals "order" (STR..CHA) or "class" (by priority)
+**/
+func rollAbilityScores(seed int64, assign string, priority []string) (AbilityScores, *AbilityRoll, error) {
+	mode := trimLower(assign)
+	if mode == "" {
+		mode = "order"
+	}
+	if mode != "order" && mode != "class" {
+		return AbilityScores{}, nil, fmt.Errorf("unknown roll assignment %q (use order or class)", assign)
+	}
+	r := newRoller(seed)
+	roll := &AbilityRoll{Seed: seed, Assign: mode}
+	var arr [6]int
+	for i := range arr {
+		total, dice := roll4d6DropLowest(r)
+		arr[i] = total
+		roll.Dice = append(roll.Dice, dice)
+		roll.Totals = append(roll.Totals, total)
+	}
+	if mode == "class" {
+		return assignByPriority(roll.Totals, priority), roll, nil
+	}
+	return abilitiesFromArray(arr), roll, nil
+}
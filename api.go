@@ -19,6 +19,9 @@ type EquipmentProvider interface {
 
 var EquipProv EquipmentProvider = &HttpEquipmentAdapter{}
 
+// LocalEquipProv answers from the equipment CSV; equipping uses it so it works offline
+var LocalEquipProv EquipmentProvider = &CSVEquipmentAdapter{}
+
 /**
 *  httpGetJSON performs an HTTP GET and decodes JSON response into v
 **/
@@ -112,17 +115,7 @@ type apiSpell struct {
 *  EnrichCharacter enriches a Character with weapon, armor, and spell data from the D&D API
 **/
 func EnrichCharacter(c *Character) {
-	if w := strings.TrimSpace(c.Equipment.Weapon); w != "" {
-		if wm, ok := EquipProv.FetchWeaponMeta(w); ok {
-			c.Equipment.WeaponInfo = wm
-		}
-	}
-
-	if a := strings.TrimSpace(c.Equipment.Armor); a != "" {
-		if am, ok := EquipProv.FetchArmorMeta(a); ok {
-			c.Equipment.ArmorInfo = am
-		}
-	}
+	EnrichEquipment(c)
 
 	if c.Spellcasting != nil {
 		for i := range c.Spellcasting.Spells {
@@ -135,3 +128,27 @@ func EnrichCharacter(c *Character) {
 		}
 	}
 }
+
+/**
+*  EnrichEquipment looks up weapon and armor data for the items in the main-hand and armor slots
+**/
+func EnrichEquipment(c *Character) {
+	enrichEquipmentFrom(c, EquipProv)
+}
+
+/**
+*  enrichEquipmentFrom fills the main-hand weapon and armor data from a provider
+**/
+func enrichEquipmentFrom(c *Character, prov EquipmentProvider) {
+	if w := strings.TrimSpace(c.Equipment.Weapon); w != "" {
+		if wm, ok := prov.FetchWeaponMeta(w); ok {
+			c.Equipment.WeaponInfo = wm
+		}
+	}
+
+	if a := strings.TrimSpace(c.Equipment.Armor); a != "" {
+		if am, ok := prov.FetchArmorMeta(a); ok {
+			c.Equipment.ArmorInfo = am
+		}
+	}
+}
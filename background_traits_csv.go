@@ -0,0 +1,91 @@
+// Layer: Infrastructure (data source adapter: load background personality tables from CSV)
+
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+const (
+	defaultBackgroundTraitsFile = "5e-SRD-Background-Traits.csv"
+	// the generic "any" tables used for backgrounds without their own; not SRD content
+	nonSRDBackgroundTraitsFile = "non-SRD-Background-Traits.csv"
+)
+
+var (
+	csvBackgroundTraits       = map[string]map[string][]TraitEntry{}
+	backgroundTraitsCSVLoaded = false
+)
+
+/**
+*  tryLoadBackgroundTraits attempts to load the background traits CSV from the first path that works
+**/
+func tryLoadBackgroundTraits(paths ...string) bool {
+	for _, p := range paths {
+		if err := loadBackgroundTraitsFromCSV(p); err == nil {
+			backgroundTraitsCSVLoaded = true
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	if p := strings.TrimSpace(os.Getenv("BACKGROUND_TRAITS_CSV")); p == "" || !tryLoadBackgroundTraits(p) {
+		_ = tryLoadBackgroundTraits(catalogPaths(defaultBackgroundTraitsFile)...)
+	}
+	if p := strings.TrimSpace(os.Getenv("NON_SRD_BACKGROUND_TRAITS_CSV")); p == "" || !tryLoadBackgroundTraits(p) {
+		_ = tryLoadBackgroundTraits(catalogPaths(nonSRDBackgroundTraitsFile)...)
+	}
+}
+
+/**
+*  loadBackgroundTraitsFromCSV parses the trait/ideal/bond/flaw tables per background ("any" is the fallback)
+*  and adds the backgrounds not loaded yet
+**/
+func loadBackgroundTraitsFromCSV(path string) error {
+	rows, err := readCatalogCSV(path)
+	if err != nil {
+		return err
+	}
+	hdr := rows[0]
+	iBg := findColumnIndex(hdr, "background")
+	iKind := findColumnIndex(hdr, "kind")
+	iEntry := findColumnIndex(hdr, "entry")
+	if iBg < 0 || iKind < 0 || iEntry < 0 {
+		return errors.New("background traits CSV missing required headers: background, kind, entry")
+	}
+	iAlign := findColumnIndex(hdr, "alignment")
+
+	tmp := make(map[string]map[string][]TraitEntry, len(csvBackgroundTraits))
+	for k, v := range csvBackgroundTraits {
+		tmp[k] = v
+	}
+	for _, row := range rows[1:] {
+		bg, kind, entry := trimLower(cellAt(row, iBg)), trimLower(cellAt(row, iKind)), cellAt(row, iEntry)
+		if bg == "" || kind == "" || entry == "" {
+			continue
+		}
+		if _, loaded := csvBackgroundTraits[bg]; loaded {
+			continue
+		}
+		if tmp[bg] == nil {
+			tmp[bg] = map[string][]TraitEntry{}
+		}
+		tmp[bg][kind] = append(tmp[bg][kind], TraitEntry{Text: entry, Alignment: trimLower(cellAt(row, iAlign))})
+	}
+	csvBackgroundTraits = tmp
+	return nil
+}
+
+/**
+*  backgroundTraitTable returns a background's table of kind (trait, ideal, bond, flaw), else the "any" table
+**/
+func backgroundTraitTable(bg, kind string) []TraitEntry {
+	if t := csvBackgroundTraits[trimLower(bg)][kind]; len(t) > 0 {
+		return t
+	}
+	return csvBackgroundTraits["any"][kind]
+}
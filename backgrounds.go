@@ -0,0 +1,21 @@
+// Layer: Domain (business rules: background choice; no IO)
+
+package main
+
+import "strings"
+
+const defaultBackground = "acolyte"
+
+/**
+*  resolveBackground picks the background (default acolyte) and validates it against the catalog
+**/
+func resolveBackground(name string) (Background, error) {
+	n := trimLower(name)
+	if n == "" {
+		n = defaultBackground
+	}
+	if bg, ok := lookupBackground(n); ok {
+		return bg, nil
+	}
+	return Background{}, unknownNameError("background", strings.TrimSpace(name), backgroundNames())
+}
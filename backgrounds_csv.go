@@ -0,0 +1,178 @@
+// Layer: Infrastructure (data source adapter: load backgrounds from CSV)
+
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultBackgroundsFile = "5e-SRD-Backgrounds.csv"
+	// backgrounds from outside SRD 5.1 (PHB), kept apart from the SRD catalog
+	nonSRDBackgroundsFile = "non-SRD-Backgrounds.csv"
+)
+
+var (
+	csvBackgrounds       = map[string]Background{}
+	backgroundsCSVLoaded = false
+)
+
+/**
+*  tryLoadBackgrounds adds the backgrounds CSV from the first path that works to the catalog;
+*  names already in the catalog are kept
+**/
+func tryLoadBackgrounds(paths ...string) bool {
+	for _, p := range paths {
+		if err := loadBackgroundsFromCSV(p); err == nil {
+			backgroundsCSVLoaded = true
+			return true
+		}
+	}
+	return false
+}
+
+/**
+*  catalogPaths lists where a data file may live: the working directory, data/ and next to the executable
+**/
+func catalogPaths(file string) []string {
+	paths := []string{
+		file,
+		filepath.Join("data", file),
+		filepath.Join("Data", file),
+		filepath.Join("DATA", file),
+	}
+	if exe, err := os.Executable(); err == nil {
+		dir := filepath.Dir(exe)
+		paths = append(paths, filepath.Join(dir, file), filepath.Join(dir, "data", file))
+	}
+	return paths
+}
+
+func init() {
+	if p := strings.TrimSpace(os.Getenv("BACKGROUNDS_CSV")); p == "" || !tryLoadBackgrounds(p) {
+		_ = tryLoadBackgrounds(catalogPaths(defaultBackgroundsFile)...)
+	}
+	if p := strings.TrimSpace(os.Getenv("NON_SRD_BACKGROUNDS_CSV")); p == "" || !tryLoadBackgrounds(p) {
+		_ = tryLoadBackgrounds(catalogPaths(nonSRDBackgroundsFile)...)
+	}
+}
+
+/**
+*  readCatalogCSV reads a whole data CSV and checks it has a header row
+**/
+func readCatalogCSV(path string) ([][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.TrimLeadingSpace = true
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New(filepath.Base(path) + " is empty")
+	}
+	return rows, nil
+}
+
+/**
+*  splitList splits a ";" separated CSV cell into trimmed, non-empty values
+**/
+func splitList(cell string) []string {
+	var out []string
+	for _, p := range strings.Split(cell, ";") {
+		if t := strings.TrimSpace(p); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+/**
+*  cellAt returns row[i] trimmed, or "" when the column is missing
+**/
+func cellAt(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+/**
+*  loadBackgroundsFromCSV parses a backgrounds CSV and adds its new names to the catalog
+**/
+func loadBackgroundsFromCSV(path string) error {
+	rows, err := readCatalogCSV(path)
+	if err != nil {
+		return err
+	}
+	hdr := rows[0]
+	iName := findColumnIndex(hdr, "name")
+	iSkills := findColumnIndex(hdr, "skills")
+	if iName < 0 || iSkills < 0 {
+		return errors.New("backgrounds CSV missing required headers: name, skills")
+	}
+	iTools := findColumnIndex(hdr, "tools")
+	iLangs := findColumnIndex(hdr, "languages")
+	iEquip := findColumnIndex(hdr, "equipment")
+	iGold := findColumnIndex(hdr, "gold")
+	iFeat := findColumnIndex(hdr, "feature")
+	iDesc := findColumnIndex(hdr, "feature_desc")
+
+	tmp := make(map[string]Background, len(csvBackgrounds)+len(rows))
+	for k, bg := range csvBackgrounds {
+		tmp[k] = bg
+	}
+	for _, row := range rows[1:] {
+		name := cellAt(row, iName)
+		if name == "" {
+			continue
+		}
+		if _, ok := tmp[trimLower(name)]; ok {
+			continue
+		}
+		gold, _ := strconv.Atoi(cellAt(row, iGold))
+		tmp[trimLower(name)] = Background{
+			Name:        name,
+			Skills:      splitList(cellAt(row, iSkills)),
+			Tools:       splitList(cellAt(row, iTools)),
+			Languages:   splitList(cellAt(row, iLangs)),
+			Equipment:   splitList(cellAt(row, iEquip)),
+			Gold:        gold,
+			Feature:     cellAt(row, iFeat),
+			FeatureDesc: cellAt(row, iDesc),
+		}
+	}
+	csvBackgrounds = tmp
+	return nil
+}
+
+/**
+*  lookupBackground returns the catalog entry for a background name
+**/
+func lookupBackground(name string) (Background, bool) {
+	bg, ok := csvBackgrounds[trimLower(name)]
+	return bg, ok
+}
+
+/**
+*  backgroundNames returns the sorted lowercase names in the catalog
+**/
+func backgroundNames() []string {
+	out := make([]string, 0, len(csvBackgrounds))
+	for k := range csvBackgrounds {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,102 @@
+// Layer: Infrastructure (data source adapter: load class starting equipment from CSV)
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const defaultClassEquipmentFile = "5e-SRD-Class-Equipment.csv"
+
+var (
+	csvClassKits            = map[string]StartingKit{}
+	classEquipmentCSVLoaded = false
+)
+
+/**
+*  tryLoadClassEquipment attempts to load the class equipment CSV from the first path that works
+**/
+func tryLoadClassEquipment(paths ...string) bool {
+	for _, p := range paths {
+		if err := loadClassEquipmentFromCSV(p); err == nil {
+			classEquipmentCSVLoaded = true
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	if p := strings.TrimSpace(os.Getenv("CLASS_EQUIPMENT_CSV")); p != "" && tryLoadClassEquipment(p) {
+		return
+	}
+	if tryLoadClassEquipment(
+		defaultClassEquipmentFile,
+		filepath.Join("data", defaultClassEquipmentFile),
+		filepath.Join("Data", defaultClassEquipmentFile),
+		filepath.Join("DATA", defaultClassEquipmentFile),
+	) {
+		return
+	}
+	if exe, err := os.Executable(); err == nil {
+		dir := filepath.Dir(exe)
+		_ = tryLoadClassEquipment(
+			filepath.Join(dir, defaultClassEquipmentFile),
+			filepath.Join(dir, "data", defaultClassEquipmentFile),
+		)
+	}
+}
+
+/**
+*  loadClassEquipmentFromCSV parses rows of class, choice, option and items; rows without a choice are
+*  given to every character of the class
+**/
+func loadClassEquipmentFromCSV(path string) error {
+	rows, err := readCatalogCSV(path)
+	if err != nil {
+		return err
+	}
+	hdr := rows[0]
+	iClass := findColumnIndex(hdr, "class")
+	iChoice := findColumnIndex(hdr, "choice")
+	iItems := findColumnIndex(hdr, "items")
+	if iClass < 0 || iChoice < 0 || iItems < 0 {
+		return errors.New("class equipment CSV missing required headers: class, choice, items")
+	}
+
+	tmp := map[string]StartingKit{}
+	for _, row := range rows[1:] {
+		class := trimLower(cellAt(row, iClass))
+		items := splitList(cellAt(row, iItems))
+		if class == "" || len(items) == 0 {
+			continue
+		}
+		kit := tmp[class]
+		kit.Class = class
+		choice, err := strconv.Atoi(cellAt(row, iChoice))
+		if err != nil || choice < 1 {
+			kit.Fixed = append(kit.Fixed, items...)
+			tmp[class] = kit
+			continue
+		}
+		for len(kit.Choices) < choice {
+			kit.Choices = append(kit.Choices, EquipmentChoice{})
+		}
+		kit.Choices[choice-1].Options = append(kit.Choices[choice-1].Options, items)
+		tmp[class] = kit
+	}
+	csvClassKits = tmp
+	return nil
+}
+
+/**
+*  lookupClassKit returns the starting equipment of a class
+**/
+func lookupClassKit(class string) (StartingKit, bool) {
+	k, ok := csvClassKits[trimLower(class)]
+	return k, ok
+}
@@ -0,0 +1,107 @@
+// Layer: Infrastructure (data source adapter: load class features by level from CSV)
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultClassFeaturesFile = "5e-SRD-Class-Features.csv"
+
+var (
+	csvClassFeatures       = map[string][]ClassFeature{}
+	classFeaturesCSVLoaded = false
+)
+
+/**
+*  tryLoadClassFeatures attempts to load the class features CSV from the first path that works
+**/
+func tryLoadClassFeatures(paths ...string) bool {
+	for _, p := range paths {
+		if err := loadClassFeaturesFromCSV(p); err == nil {
+			classFeaturesCSVLoaded = true
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	if p := strings.TrimSpace(os.Getenv("CLASS_FEATURES_CSV")); p != "" && tryLoadClassFeatures(p) {
+		return
+	}
+	if tryLoadClassFeatures(
+		defaultClassFeaturesFile,
+		filepath.Join("data", defaultClassFeaturesFile),
+		filepath.Join("Data", defaultClassFeaturesFile),
+		filepath.Join("DATA", defaultClassFeaturesFile),
+	) {
+		return
+	}
+	if exe, err := os.Executable(); err == nil {
+		dir := filepath.Dir(exe)
+		_ = tryLoadClassFeatures(
+			filepath.Join(dir, defaultClassFeaturesFile),
+			filepath.Join(dir, "data", defaultClassFeaturesFile),
+		)
+	}
+}
+
+/**
+*  loadClassFeaturesFromCSV parses the class features CSV into per-class lists sorted by level
+**/
+func loadClassFeaturesFromCSV(path string) error {
+	rows, err := readCatalogCSV(path)
+	if err != nil {
+		return err
+	}
+	hdr := rows[0]
+	iClass := findColumnIndex(hdr, "class")
+	iLevel := findColumnIndex(hdr, "level")
+	iFeature := findColumnIndex(hdr, "feature")
+	iSubclass := findColumnIndex(hdr, "subclass")
+	if iClass < 0 || iLevel < 0 || iFeature < 0 {
+		return errors.New("class features CSV missing required headers: class, level, feature")
+	}
+
+	tmp := map[string][]ClassFeature{}
+	for _, row := range rows[1:] {
+		class := trimLower(cellAt(row, iClass))
+		name := cellAt(row, iFeature)
+		lvl, err := strconv.Atoi(cellAt(row, iLevel))
+		if class == "" || name == "" || err != nil {
+			continue
+		}
+		tmp[class] = append(tmp[class], ClassFeature{Class: class, Subclass: cellAt(row, iSubclass), Level: lvl, Name: name})
+	}
+	for k := range tmp {
+		sort.SliceStable(tmp[k], func(i, j int) bool { return tmp[k][i].Level < tmp[k][j].Level })
+	}
+	csvClassFeatures = tmp
+	return nil
+}
+
+/**
+*  classFeaturesBetween returns the base class features gained after level from, up to and including level to
+**/
+func classFeaturesBetween(class string, from, to int) []ClassFeature {
+	return subclassFeaturesBetween(class, "", from, to)
+}
+
+/**
+*  subclassFeaturesBetween is classFeaturesBetween for one subclass ("" means the base class)
+**/
+func subclassFeaturesBetween(class, subclass string, from, to int) []ClassFeature {
+	var out []ClassFeature
+	for _, f := range csvClassFeatures[trimLower(class)] {
+		if strings.EqualFold(f.Subclass, subclass) && f.Level > from && f.Level <= to {
+			out = append(out, f)
+		}
+	}
+	return out
+}
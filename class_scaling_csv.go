@@ -0,0 +1,114 @@
+// Layer: Infrastructure (data source adapter: load per-level class feature numbers from CSV)
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const defaultClassScalingFile = "5e-SRD-Class-Scaling.csv"
+
+var (
+	csvClassScaling       = map[string][]FeatureScaling{}
+	classScalingCSVLoaded = false
+)
+
+/**
+*  tryLoadClassScaling attempts to load the class scaling CSV from the first path that works
+**/
+func tryLoadClassScaling(paths ...string) bool {
+	for _, p := range paths {
+		if err := loadClassScalingFromCSV(p); err == nil {
+			classScalingCSVLoaded = true
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	if p := strings.TrimSpace(os.Getenv("CLASS_SCALING_CSV")); p != "" && tryLoadClassScaling(p) {
+		return
+	}
+	if tryLoadClassScaling(
+		defaultClassScalingFile,
+		filepath.Join("data", defaultClassScalingFile),
+		filepath.Join("Data", defaultClassScalingFile),
+		filepath.Join("DATA", defaultClassScalingFile),
+	) {
+		return
+	}
+	if exe, err := os.Executable(); err == nil {
+		dir := filepath.Dir(exe)
+		_ = tryLoadClassScaling(
+			filepath.Join(dir, defaultClassScalingFile),
+			filepath.Join(dir, "data", defaultClassScalingFile),
+		)
+	}
+}
+
+/**
+*  parseLevelStrings parses "1:d6;5:d8" into level -> value; values may contain spaces but not ";"
+**/
+func parseLevelStrings(cell string) map[int]string {
+	out := map[int]string{}
+	for _, part := range splitList(cell) {
+		lv, val, ok := strings.Cut(part, ":")
+		if l, err := strconv.Atoi(strings.TrimSpace(lv)); ok && err == nil {
+			out[l] = strings.TrimSpace(val)
+		}
+	}
+	return out
+}
+
+/**
+*  loadClassScalingFromCSV parses the scaling CSV into per-class rows, keeping the file's label order
+**/
+func loadClassScalingFromCSV(path string) error {
+	rows, err := readCatalogCSV(path)
+	if err != nil {
+		return err
+	}
+	hdr := rows[0]
+	iClass := findColumnIndex(hdr, "class")
+	iFeature := findColumnIndex(hdr, "feature")
+	iLabel := findColumnIndex(hdr, "label")
+	iValues := findColumnIndex(hdr, "values")
+	if iClass < 0 || iFeature < 0 || iLabel < 0 || iValues < 0 {
+		return errors.New("class scaling CSV missing required headers: class, feature, label, values")
+	}
+
+	tmp := map[string][]FeatureScaling{}
+	for _, row := range rows[1:] {
+		class := trimLower(cellAt(row, iClass))
+		feature := cellAt(row, iFeature)
+		if class == "" || feature == "" {
+			continue
+		}
+		tmp[class] = append(tmp[class], FeatureScaling{
+			Class:   class,
+			Feature: feature,
+			Label:   cellAt(row, iLabel),
+			Values:  parseLevelStrings(cellAt(row, iValues)),
+		})
+	}
+	csvClassScaling = tmp
+	return nil
+}
+
+/**
+*  featureScaling returns the scaling rows of one class feature
+**/
+func featureScaling(class, feature string) []FeatureScaling {
+	var out []FeatureScaling
+	for _, s := range csvClassScaling[trimLower(class)] {
+		if strings.EqualFold(s.Feature, feature) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
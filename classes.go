@@ -0,0 +1,39 @@
+// Layer: Domain (business rules: class catalog queries; no IO)
+
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+/**
+*  resolveClass validates a class against the catalog; an empty class is allowed
+**/
+func resolveClass(name string) (ClassInfo, error) {
+	if strings.TrimSpace(name) == "" {
+		return ClassInfo{Caster: "none"}, nil
+	}
+	if c, ok := lookupClass(name); ok {
+		return c, nil
+	}
+	return ClassInfo{}, unknownNameError("class", strings.TrimSpace(name), classNames())
+}
+
+/**
+*  levelTableValue returns the value of the highest threshold at or below level
+**/
+func levelTableValue(table map[int]int, level int) int {
+	keys := make([]int, 0, len(table))
+	for k := range table {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	val := 0
+	for _, k := range keys {
+		if k <= level {
+			val = table[k]
+		}
+	}
+	return val
+}
@@ -0,0 +1,162 @@
+// Layer: Infrastructure (data source adapter: load the class catalog from CSV)
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultClassesFile = "5e-SRD-Classes.csv"
+
+var (
+	csvClasses       = map[string]ClassInfo{}
+	classesCSVLoaded = false
+)
+
+/**
+*  tryLoadClasses attempts to load the classes CSV from the first path that works
+**/
+func tryLoadClasses(paths ...string) bool {
+	for _, p := range paths {
+		if err := loadClassesFromCSV(p); err == nil {
+			classesCSVLoaded = true
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	if p := strings.TrimSpace(os.Getenv("CLASSES_CSV")); p != "" && tryLoadClasses(p) {
+		return
+	}
+	if tryLoadClasses(
+		defaultClassesFile,
+		filepath.Join("data", defaultClassesFile),
+		filepath.Join("Data", defaultClassesFile),
+		filepath.Join("DATA", defaultClassesFile),
+	) {
+		return
+	}
+	if exe, err := os.Executable(); err == nil {
+		dir := filepath.Dir(exe)
+		_ = tryLoadClasses(
+			filepath.Join(dir, defaultClassesFile),
+			filepath.Join(dir, "data", defaultClassesFile),
+		)
+	}
+}
+
+/**
+*  parseLevelTable parses "1:3;4:4;10:5" into a level threshold -> value map
+**/
+func parseLevelTable(cell string) map[int]int {
+	out := map[int]int{}
+	for _, part := range splitList(cell) {
+		lv, val, ok := strings.Cut(part, ":")
+		if !ok {
+			continue
+		}
+		l, err1 := strconv.Atoi(strings.TrimSpace(lv))
+		v, err2 := strconv.Atoi(strings.TrimSpace(val))
+		if err1 == nil && err2 == nil {
+			out[l] = v
+		}
+	}
+	return out
+}
+
+/**
+*  loadClassesFromCSV parses the classes CSV into the name-keyed catalog
+**/
+func loadClassesFromCSV(path string) error {
+	rows, err := readCatalogCSV(path)
+	if err != nil {
+		return err
+	}
+	hdr := rows[0]
+	iName := findColumnIndex(hdr, "name")
+	iHitDie := findColumnIndex(hdr, "hit_die")
+	if iName < 0 || iHitDie < 0 {
+		return errors.New("classes CSV missing required headers: name, hit_die")
+	}
+	iPrimary := findColumnIndex(hdr, "primary")
+	iPriority := findColumnIndex(hdr, "priority")
+	iSaves := findColumnIndex(hdr, "saves")
+	iArmor := findColumnIndex(hdr, "armor")
+	iWeapons := findColumnIndex(hdr, "weapons")
+	iTools := findColumnIndex(hdr, "tools")
+	iSkillCount := findColumnIndex(hdr, "skill_count")
+	iSkills := findColumnIndex(hdr, "skills")
+	iCaster := findColumnIndex(hdr, "caster")
+	iAbility := findColumnIndex(hdr, "spell_ability")
+	iLearns := findColumnIndex(hdr, "learns")
+	iPrepares := findColumnIndex(hdr, "prepares")
+	iCantrips := findColumnIndex(hdr, "cantrips")
+	iExpertise := findColumnIndex(hdr, "expertise")
+	iMulticlass := findColumnIndex(hdr, "multiclass")
+	iGold := findColumnIndex(hdr, "starting_gold")
+	iKnown := findColumnIndex(hdr, "spells_known")
+
+	tmp := map[string]ClassInfo{}
+	for _, row := range rows[1:] {
+		name := cellAt(row, iName)
+		if name == "" {
+			continue
+		}
+		caster := trimLower(cellAt(row, iCaster))
+		if caster == "" {
+			caster = "none"
+		}
+		learns, _ := strconv.ParseBool(cellAt(row, iLearns))
+		prepares, _ := strconv.ParseBool(cellAt(row, iPrepares))
+		tmp[trimLower(name)] = ClassInfo{
+			Name:         name,
+			HitDie:       atoiCell(row, iHitDie),
+			Primary:      trimLower(cellAt(row, iPrimary)),
+			Priority:     splitList(trimLower(cellAt(row, iPriority))),
+			Saves:        splitList(trimLower(cellAt(row, iSaves))),
+			Armor:        splitList(cellAt(row, iArmor)),
+			Weapons:      splitList(cellAt(row, iWeapons)),
+			Tools:        splitList(cellAt(row, iTools)),
+			SkillCount:   atoiCell(row, iSkillCount),
+			Skills:       splitList(cellAt(row, iSkills)),
+			Caster:       caster,
+			SpellAbility: trimLower(cellAt(row, iAbility)),
+			Learns:       learns,
+			Prepares:     prepares,
+			Cantrips:     parseLevelTable(cellAt(row, iCantrips)),
+			Expertise:    parseLevelTable(cellAt(row, iExpertise)),
+			Multiclass:   splitList(trimLower(cellAt(row, iMulticlass))),
+			StartingGold: trimLower(cellAt(row, iGold)),
+			SpellsKnown:  parseLevelTable(cellAt(row, iKnown)),
+		}
+	}
+	csvClasses = tmp
+	return nil
+}
+
+/**
+*  lookupClass returns the catalog entry for a class name
+**/
+func lookupClass(name string) (ClassInfo, bool) {
+	c, ok := csvClasses[trimLower(name)]
+	return c, ok
+}
+
+/**
+*  classNames returns the sorted lowercase class names in the catalog
+**/
+func classNames() []string {
+	out := make([]string, 0, len(csvClasses))
+	for k := range csvClasses {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
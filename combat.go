@@ -118,14 +118,57 @@ func computeInitiativeBonus(c *Character) int { return dexModOf(c) }
 
 
 /**
-* computePassivePerception returns 10 + Wis mod
+* computePassivePerception returns 10 + the Perception skill modifier
 **/
 func computePassivePerception(c *Character) int {
-	pp := 10 + wisModOf(c)
-	if hasSkill(c, "perception") {
-		pp += c.ProficiencyBonus
+	return 10 + skillModifier(c, "perception").Modifier
+}
+
+/**
+* isSaveProficient reports whether the character's class grants proficiency in the ability's save
+**/
+func isSaveProficient(c *Character, ability string) bool {
+	ci, ok := lookupClass(c.Class)
+	if !ok {
+		return false
+	}
+	idx := abilityIndex(ability)
+	for _, s := range ci.Saves {
+		if abilityIndex(s) == idx {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+* computeSavingThrows returns the six saves: ability mod plus proficiency bonus when proficient
+**/
+func computeSavingThrows(c *Character) []SavingThrow {
+	scores := abilityArray(c.AbilityScores)
+	out := make([]SavingThrow, 0, len(scores))
+	for i, ab := range abilityShortNames {
+		st := SavingThrow{Ability: ab, Modifier: abilityMod(scores[i])}
+		if isSaveProficient(c, ab) {
+			st.Proficient = true
+			st.Modifier += c.ProficiencyBonus
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+/**
+* computeDerivedStats gathers the computed sheet values for the API
+**/
+func computeDerivedStats(c *Character) DerivedStats {
+	return DerivedStats{
+		ArmorClass:        computeArmorClass(c),
+		Initiative:        computeInitiativeBonus(c),
+		PassivePerception: computePassivePerception(c),
+		SavingThrows:      computeSavingThrows(c),
+		Skills:            computeSkillTable(c),
 	}
-	return pp
 }
 
 /**
@@ -137,6 +180,16 @@ func computeWeaponDamageString(c *Character) string {
 		return ""
 	}
 	wi := c.Equipment.WeaponInfo
+	if strings.TrimSpace(wi.DamageDice) == "" {
+		wi.DamageDice = equipmentDamage(w)
+		wi.Finesse = hasWeaponProperty(w, "finesse")
+		if strings.HasSuffix(equipmentCategory(w), " ranged") {
+			wi.WeaponRange = "ranged"
+		}
+	}
+	if die := versatileDie(w); die != "" && c.Equipment.OffHand == "" && c.Equipment.Shield == "" {
+		wi.DamageDice = die
+	}
 	if strings.TrimSpace(wi.DamageDice) == "" {
 		return ""
 	}
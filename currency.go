@@ -0,0 +1,283 @@
+// Layer: Domain (business rules: coin purse, change-making, prices and starting gold; no IO)
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+type coin struct {
+	Name  string
+	Value int
+}
+
+// coins from smallest to largest, valued in copper pieces
+var coins = []coin{{"cp", 1}, {"sp", 10}, {"ep", 50}, {"gp", 100}, {"pp", 1000}}
+
+// change comes back in the everyday coins only
+var changeCoins = []coin{{"gp", 100}, {"sp", 10}, {"cp", 1}}
+
+/**
+*  purseCoins returns pointers to the purse counts in the order of coins
+**/
+func purseCoins(p *Purse) []*int {
+	return []*int{&p.CP, &p.SP, &p.EP, &p.GP, &p.PP}
+}
+
+/**
+*  purseValue returns the total worth of a purse in copper pieces
+**/
+func purseValue(p Purse) int {
+	total := 0
+	for i, n := range purseCoins(&p) {
+		total += *n * coins[i].Value
+	}
+	return total
+}
+
+/**
+*  coinIndex returns the position of "cp", "sp", "ep", "gp" or "pp" in coins, or -1
+**/
+func coinIndex(name string) int {
+	n := trimLower(name)
+	for i, c := range coins {
+		if n == c.Name {
+			return i
+		}
+	}
+	return -1
+}
+
+/**
+*  parseCoins reads an amount like "15 gp", "2gp 5sp" or "1 gp, 3 cp" into a purse
+**/
+func parseCoins(s string) (Purse, error) {
+	var p Purse
+	fields := strings.Fields(strings.ReplaceAll(strings.ToLower(s), ",", " "))
+	if len(fields) == 0 {
+		return p, fmt.Errorf("no amount given (use e.g. \"15 gp\" or \"2 gp 5 sp\")")
+	}
+	counts := purseCoins(&p)
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		num := strings.TrimRight(f, "cspegp")
+		unit := f[len(num):]
+		if unit == "" && i+1 < len(fields) {
+			i++
+			unit = fields[i]
+		}
+		n, err := strconv.Atoi(num)
+		idx := coinIndex(unit)
+		if err != nil || n < 0 || idx < 0 {
+			return Purse{}, fmt.Errorf("invalid amount %q (use e.g. \"15 gp\" or \"2 gp 5 sp\")", s)
+		}
+		*counts[idx] += n
+	}
+	return p, nil
+}
+
+/**
+*  parseCost reads a single price like "15 gp" into copper pieces
+**/
+func parseCost(s string) (int, error) {
+	p, err := parseCoins(s)
+	if err != nil {
+		return 0, err
+	}
+	return purseValue(p), nil
+}
+
+/**
+*  formatPurse lists the coins in a purse, largest first ("3 gp, 5 sp"); an empty purse is "0 gp"
+**/
+func formatPurse(p Purse) string {
+	counts := purseCoins(&p)
+	var parts []string
+	for i := len(coins) - 1; i >= 0; i-- {
+		if *counts[i] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", *counts[i], coins[i].Name))
+		}
+	}
+	if len(parts) == 0 {
+		return "0 gp"
+	}
+	return strings.Join(parts, ", ")
+}
+
+/**
+*  formatCost prints copper pieces in gp, sp and cp ("1 gp, 5 sp")
+**/
+func formatCost(cp int) string {
+	return formatPurse(makeChange(cp))
+}
+
+/**
+*  makeChange splits copper pieces into as few gp, sp and cp as possible
+**/
+func makeChange(cp int) Purse {
+	var p Purse
+	counts := purseCoins(&p)
+	for _, c := range changeCoins {
+		*counts[coinIndex(c.Name)] += cp / c.Value
+		cp %= c.Value
+	}
+	return p
+}
+
+/**
+*  addCoins puts two purses together
+**/
+func addCoins(a, b Purse) Purse {
+	x, y := purseCoins(&a), purseCoins(&b)
+	for i := range x {
+		*x[i] += *y[i]
+	}
+	return a
+}
+
+/**
+*  spendCoins pays cp copper pieces from a purse, using the smallest coins first; when the small coins
+*  do not cover the rest, the smallest coin that does is broken and the change comes back in gp, sp and cp
+**/
+func spendCoins(p Purse, cp int) (Purse, error) {
+	if cp < 0 {
+		return p, fmt.Errorf("cannot spend a negative amount")
+	}
+	if have := purseValue(p); have < cp {
+		return p, fmt.Errorf("not enough money: costs %s, purse holds %s", formatCost(cp), formatCost(have))
+	}
+	counts := purseCoins(&p)
+	rest := cp
+	for i, c := range coins {
+		use := min(*counts[i], rest/c.Value)
+		*counts[i] -= use
+		rest -= use * c.Value
+	}
+	if rest == 0 {
+		return p, nil
+	}
+	for i, c := range coins {
+		if *counts[i] > 0 && c.Value > rest {
+			*counts[i]--
+			return addCoins(p, makeChange(c.Value-rest)), nil
+		}
+	}
+	return p, fmt.Errorf("cannot make change for %s", formatCost(cp))
+}
+
+/**
+*  itemCost returns the SRD price in copper pieces of qty items; bundles ("arrows (20) 1 gp") are
+*  prorated and rounded up
+**/
+func itemCost(name string, qty int) (int, error) {
+	cost, per, ok := equipmentPrice(name)
+	if !ok {
+		return 0, fmt.Errorf("%q has no SRD price", name)
+	}
+	return (cost*qty + per - 1) / per, nil
+}
+
+/**
+*  buyItem pays for qty items from the character's purse and adds them to the inventory
+**/
+func buyItem(c *Character, name string, qty int) (int, error) {
+	if qty < 1 {
+		return 0, fieldError("quantity", fmt.Errorf("quantity must be at least 1 (got %d)", qty))
+	}
+	cost, err := itemCost(name, qty)
+	if err != nil {
+		return 0, fieldError("item", err)
+	}
+	purse, err := spendCoins(c.Purse, cost)
+	if err != nil {
+		return 0, fieldError("purse", err)
+	}
+	if _, err := addItem(c, Item{Name: name, Quantity: qty, Weight: -1}); err != nil {
+		return 0, err
+	}
+	c.Purse = purse
+	return cost, nil
+}
+
+/**
+*  sellItem removes qty items from the inventory and pays half the SRD price into the purse
+**/
+func sellItem(c *Character, name string, qty int) (int, error) {
+	if qty < 1 {
+		return 0, fieldError("quantity", fmt.Errorf("quantity must be at least 1 (got %d)", qty))
+	}
+	cost, err := itemCost(name, qty)
+	if err != nil {
+		return 0, fieldError("item", err)
+	}
+	if _, err := removeItem(c, name, qty); err != nil {
+		return 0, err
+	}
+	price := cost / 2
+	c.Purse = addCoins(c.Purse, makeChange(price))
+	return price, nil
+}
+
+/**
+*  parseStartingGold reads a class starting-wealth formula like "5d4x10" into its dice and multiplier
+**/
+func parseStartingGold(formula string) (string, int) {
+	dice, mult, ok := strings.Cut(trimLower(formula), "x")
+	if !ok {
+		return dice, 1
+	}
+	m, err := strconv.Atoi(strings.TrimSpace(mult))
+	if err != nil || m < 1 {
+		m = 1
+	}
+	return strings.TrimSpace(dice), m
+}
+
+/**
+*  startingGold returns a class's starting wealth in gp, rolled ("roll") or averaged ("average")
+**/
+func startingGold(class, method string, r *rand.Rand) (int, error) {
+	m, err := normalizeHPMethod(method)
+	if err != nil {
+		return 0, fmt.Errorf("unknown gold method %q (use avg or roll)", method)
+	}
+	ci, ok := lookupClass(class)
+	if !ok || ci.StartingGold == "" {
+		return 0, fmt.Errorf("no starting gold for class %q", class)
+	}
+	dice, mult := parseStartingGold(ci.StartingGold)
+	if m == "roll" {
+		n, err := rollDiceExpr(r, dice)
+		return n * mult, err
+	}
+	count, sides, _ := strings.Cut(dice, "d")
+	n, _ := strconv.Atoi(count)
+	s, _ := strconv.Atoi(sides)
+	return n * (s + 1) * mult / 2, nil
+}
+
+/**
+*  grantStartingGold adds the class's starting wealth to the purse and returns the gp granted
+**/
+func grantStartingGold(c *Character, method string, r *rand.Rand) (int, error) {
+	gp, err := startingGold(c.Class, method, r)
+	if err != nil {
+		return 0, err
+	}
+	c.Purse.GP += gp
+	return gp, nil
+}
+
+/**
+*  coinWeight returns the weight of the coins in a purse (50 coins weigh a pound)
+**/
+func coinWeight(p Purse) float64 {
+	n := 0
+	for _, c := range purseCoins(&p) {
+		n += *c
+	}
+	return float64(n) / 50
+}
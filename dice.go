@@ -0,0 +1,70 @@
+// Layer: Domain (business rules: seeded dice rolling; no IO)
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/**
+*  newSeed returns a fresh seed for rolls the user did not seed explicitly
+**/
+func newSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+/**
+*  newRoller returns a deterministic random source for the seed
+**/
+func newRoller(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+/**
+*  rollDie rolls a single die with the given number of sides
+**/
+func rollDie(r *rand.Rand, sides int) int {
+	if sides < 1 {
+		return 0
+	}
+	return r.Intn(sides) + 1
+}
+
+/**
+*  roll4d6DropLowest rolls 4d6 and returns the sum of the highest three plus the raw dice
+**/
+func roll4d6DropLowest(r *rand.Rand) (int, []int) {
+	dice := make([]int, 4)
+	for i := range dice {
+		dice[i] = rollDie(r, 6)
+	}
+	sorted := append([]int(nil), dice...)
+	sort.Ints(sorted)
+	return sorted[1] + sorted[2] + sorted[3], dice
+}
+
+/**
+*  rollDiceExpr rolls "NdM" and sums the dice; a plain number is returned as is
+**/
+func rollDiceExpr(r *rand.Rand, expr string) (int, error) {
+	expr = strings.ToLower(strings.TrimSpace(expr))
+	if n, err := strconv.Atoi(expr); err == nil {
+		return n, nil
+	}
+	count, sides, ok := strings.Cut(expr, "d")
+	n, err1 := strconv.Atoi(count)
+	s, err2 := strconv.Atoi(sides)
+	if !ok || err1 != nil || err2 != nil || n < 1 || s < 1 {
+		return 0, fmt.Errorf("invalid dice %q (use NdM)", expr)
+	}
+	total := 0
+	for i := 0; i < n; i++ {
+		total += rollDie(r, s)
+	}
+	return total, nil
+}
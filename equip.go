@@ -0,0 +1,333 @@
+// Layer: Domain (business rules: equipment slots, replacing, unequipping and swapping hands; no IO)
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	slotMain   = "main hand"
+	slotOff    = "off hand"
+	slotArmor  = "armor"
+	slotShield = "shield"
+)
+
+// request field that names each slot's item (matches the create request)
+var slotFields = map[string]string{slotMain: "weapon", slotOff: "offhand", slotArmor: "armor", slotShield: "shield"}
+
+/**
+*  parseSlot reads a slot name: "main" ("mainhand", "main hand", "weapon"), "off" ("offhand", "off hand"),
+*  "armor" or "shield"
+**/
+func parseSlot(s string) (string, error) {
+	switch strings.Join(strings.Fields(strings.ReplaceAll(trimLower(s), "-", " ")), " ") {
+	case "main", "mainhand", "main hand", "weapon":
+		return slotMain, nil
+	case "off", "offhand", "off hand":
+		return slotOff, nil
+	case "armor", "armour":
+		return slotArmor, nil
+	case "shield":
+		return slotShield, nil
+	}
+	return "", fmt.Errorf("unknown slot %q (use main, off, armor or shield)", s)
+}
+
+/**
+*  slotItem returns a pointer to the item name held in a slot
+**/
+func slotItem(e *Equipment, slot string) *string {
+	switch slot {
+	case slotMain:
+		return &e.Weapon
+	case slotOff:
+		return &e.OffHand
+	case slotArmor:
+		return &e.Armor
+	case slotShield:
+		return &e.Shield
+	}
+	return nil
+}
+
+/**
+*  clearSlotInfo drops the looked-up weapon or armor data once the item in its slot changes
+**/
+func clearSlotInfo(c *Character, slot string) {
+	switch slot {
+	case slotMain:
+		c.Equipment.WeaponInfo = WeaponMeta{}
+	case slotArmor:
+		c.Equipment.ArmorInfo = ArmorMeta{}
+	}
+}
+
+/**
+*  releaseItem marks an item that left its slot as carried again, unless another slot still holds it
+**/
+func releaseItem(c *Character, name string) {
+	if name == "" {
+		return
+	}
+	for _, s := range equippedSlotItems(c.Equipment) {
+		if sameItem(s, name) {
+			return
+		}
+	}
+	if i := findItem(c.Inventory, name, "", false); i >= 0 {
+		c.Inventory[i].Equipped = false
+	}
+}
+
+/**
+*  itemSlot returns the slot a weapon, armor or shield is equipped in ("" for other items); weapons go to the main hand
+**/
+func itemSlot(name string) string {
+	switch t, _ := equipmentType(normalizeEquipment(name)); {
+	case t == "weapon":
+		return slotMain
+	case t == "armor" && equipmentCategory(name) == "shield":
+		return slotShield
+	case t == "armor":
+		return slotArmor
+	}
+	return ""
+}
+
+/**
+*  carryItem adds an item like addItem; an equipped weapon, armor or shield is put in its slot (which must be free)
+*  so the flag survives the next reconcile with the slots
+**/
+func carryItem(c *Character, item Item) (Item, error) {
+	slot := itemSlot(item.Name)
+	if !item.Equipped || slot == "" {
+		return addItem(c, item)
+	}
+	item.Equipped = false
+	it, err := addItem(c, item)
+	if err != nil {
+		return Item{}, err
+	}
+	if _, err := equipSlot(c, slot, it.Name, false); err != nil {
+		return Item{}, err
+	}
+	it.Equipped = true
+	return it, nil
+}
+
+/**
+*  hasWeaponProperty reports whether a weapon has a property; "versatile" matches "versatile (1d10)"
+**/
+func hasWeaponProperty(name, prop string) bool {
+	for _, p := range equipmentProperties(name) {
+		if p == prop || strings.HasPrefix(p, prop+" (") {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+*  versatileDie returns the two-handed damage of a versatile weapon ("longsword" -> "1d10"), or ""
+**/
+func versatileDie(name string) string {
+	for _, p := range equipmentProperties(name) {
+		if die, ok := strings.CutPrefix(p, "versatile ("); ok {
+			return strings.TrimSuffix(die, ")")
+		}
+	}
+	return ""
+}
+
+/**
+*  isTwoHanded reports whether the item in a hand needs both hands (the CSV property, or the looked-up main-hand data)
+**/
+func isTwoHanded(e Equipment, slot string) bool {
+	item := *slotItem(&e, slot)
+	if item == "" {
+		return false
+	}
+	return hasWeaponProperty(item, "two-handed") || (slot == slotMain && e.WeaponInfo.TwoHanded)
+}
+
+/**
+*  handConflicts lists the occupied slots that clash with the item in slot and why: a two-handed weapon needs
+*  the off hand and the shield free, and a shield and an off-hand item need the same hand
+**/
+func handConflicts(e Equipment, slot string) ([]string, string) {
+	var slots, reasons []string
+	clash := func(other, reason string) {
+		if *slotItem(&e, other) == "" || containsString(slots, other) {
+			return
+		}
+		slots = append(slots, other)
+		if !containsString(reasons, reason) {
+			reasons = append(reasons, reason)
+		}
+	}
+	twoHanded := fmt.Sprintf("%s is two-handed", e.Weapon)
+	sameHand := "a shield and an off-hand item need the same hand"
+	switch slot {
+	case slotMain:
+		if isTwoHanded(e, slotMain) {
+			clash(slotOff, twoHanded)
+			clash(slotShield, twoHanded)
+		}
+	case slotOff:
+		if isTwoHanded(e, slotMain) {
+			clash(slotMain, twoHanded)
+		}
+		clash(slotShield, sameHand)
+	case slotShield:
+		if isTwoHanded(e, slotMain) {
+			clash(slotMain, twoHanded)
+		}
+		clash(slotOff, sameHand)
+	}
+	return slots, strings.Join(reasons, "; ")
+}
+
+/**
+*  checkHands validates hand occupancy of a whole loadout, blaming the off hand or the shield
+**/
+func checkHands(e Equipment) error {
+	var verr ValidationError
+	if isTwoHanded(e, slotOff) {
+		verr.add(slotFields[slotOff], "%s is two-handed; wield it in the main hand", e.OffHand)
+	}
+	blamed := map[string]bool{}
+	for _, slot := range []string{slotMain, slotOff} {
+		if *slotItem(&e, slot) == "" {
+			continue
+		}
+		slots, why := handConflicts(e, slot)
+		for _, s := range slots {
+			if s != slotMain && !blamed[s] {
+				blamed[s] = true
+				verr.add(slotFields[s], "%s", why)
+			}
+		}
+	}
+	return verr.errOrNil()
+}
+
+/**
+*  handWarnings lists loadouts that are allowed but limited: an off-hand attack needs a light weapon in each hand
+**/
+func handWarnings(e Equipment) []string {
+	off := e.OffHand
+	if t, _ := equipmentType(off); off == "" || t != "weapon" {
+		return nil
+	}
+	var out []string
+	if !hasWeaponProperty(off, "light") {
+		out = append(out, fmt.Sprintf("%s in the off hand is not light: no off-hand attack", off))
+	}
+	if e.Weapon != "" && !hasWeaponProperty(e.Weapon, "light") {
+		out = append(out, fmt.Sprintf("%s in the main hand is not light: no off-hand attack", e.Weapon))
+	}
+	return out
+}
+
+/**
+*  describeSlots lists slots with their items ("off hand (dagger) and shield (shield)")
+**/
+func describeSlots(e Equipment, slots []string) string {
+	parts := make([]string, 0, len(slots))
+	for _, s := range slots {
+		parts = append(parts, fmt.Sprintf("%s (%s)", s, *slotItem(&e, s)))
+	}
+	return strings.Join(parts, " and ")
+}
+
+/**
+*  equipSlot puts an item into a slot and returns the items it displaced; an occupied slot, or a hand the
+*  item needs, is only emptied with replace, and displaced items stay in the inventory
+**/
+func equipSlot(c *Character, slot, item string, replace bool) ([]string, error) {
+	e := c.Equipment
+	p := slotItem(&e, slot)
+	if p == nil {
+		return nil, fieldError("slot", fmt.Errorf("unknown slot %q", slot))
+	}
+	field := slotFields[slot]
+	item = normalizeEquipment(item)
+	if item == "" {
+		return nil, fieldError(field, fmt.Errorf("an item is required"))
+	}
+	old := *p
+	if old != "" && sameItem(old, item) {
+		ensureEquipmentInInventory(c)
+		return nil, nil
+	}
+	if old != "" && !replace {
+		return nil, fieldError(field, fmt.Errorf("%s already holds %s (replace it or unequip first)", slot, old))
+	}
+	if slot == slotOff && hasWeaponProperty(item, "two-handed") {
+		return nil, fieldError(field, fmt.Errorf("%s is two-handed; wield it in the main hand", item))
+	}
+	*p = item
+	if slot == slotMain {
+		e.WeaponInfo = WeaponMeta{}
+	}
+	var displaced []string
+	if old != "" {
+		displaced = append(displaced, old)
+	}
+	slots, why := handConflicts(e, slot)
+	if len(slots) > 0 && !replace {
+		return nil, fieldError(field, fmt.Errorf("%s, so %s must be emptied (replace or unequip first)", why, describeSlots(e, slots)))
+	}
+	for _, s := range slots {
+		displaced = append(displaced, *slotItem(&e, s))
+		*slotItem(&e, s) = ""
+	}
+	c.Equipment = e
+	for _, s := range append(slots, slot) {
+		clearSlotInfo(c, s)
+	}
+	ensureEquipmentInInventory(c)
+	for _, d := range displaced {
+		releaseItem(c, d)
+	}
+	return displaced, nil
+}
+
+/**
+*  unequipSlot empties a slot and returns the item it held, which stays in the inventory
+**/
+func unequipSlot(c *Character, slot string) (string, error) {
+	p := slotItem(&c.Equipment, slot)
+	if p == nil {
+		return "", fieldError("slot", fmt.Errorf("unknown slot %q", slot))
+	}
+	old := *p
+	if old == "" {
+		return "", fieldError("slot", fmt.Errorf("%s is already empty", slot))
+	}
+	*p = ""
+	clearSlotInfo(c, slot)
+	ensureEquipmentInInventory(c)
+	releaseItem(c, old)
+	return old, nil
+}
+
+/**
+*  swapHands exchanges the main-hand and off-hand items
+**/
+func swapHands(c *Character) error {
+	e := &c.Equipment
+	if e.Weapon == "" && e.OffHand == "" {
+		return fieldError("slot", fmt.Errorf("both hands are empty"))
+	}
+	swapped := *e
+	swapped.Weapon, swapped.OffHand = e.OffHand, e.Weapon
+	swapped.WeaponInfo = WeaponMeta{}
+	if err := checkHands(swapped); err != nil {
+		return err
+	}
+	*e = swapped
+	return nil
+}
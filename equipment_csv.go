@@ -7,6 +7,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -16,8 +17,17 @@ const (
 )
 
 var (
-	csvEquipmentTypeByName = map[string]string{}
-	equipmentCSVLoaded     = false
+	csvEquipmentTypeByName   = map[string]string{}
+	csvEquipmentWeightByName = map[string]float64{}
+	csvEquipmentDisplayName  = map[string]string{}
+	csvEquipmentCostByName   = map[string]int{}
+	csvEquipmentPerByName    = map[string]int{}
+	csvEquipmentCategory     = map[string]string{}
+	csvEquipmentNames        = []string{}
+	csvEquipmentDamage       = map[string]string{}
+	csvEquipmentProperties   = map[string][]string{}
+	csvEquipmentArmorClass   = map[string]int{}
+	equipmentCSVLoaded       = false
 )
 
 /**
@@ -103,6 +113,98 @@ func buildNameTypeIndex(rows [][]string, iName, iType int) map[string]string {
 	return tmp
 }
 
+/**
+*  buildWeightIndex maps lowercase names to their weight in pounds and to the CSV spelling
+**/
+func buildWeightIndex(rows [][]string, iName, iWeight int) (map[string]float64, map[string]string) {
+	weights := make(map[string]float64, len(rows))
+	names := make(map[string]string, len(rows))
+	for _, row := range rows[1:] {
+		name := strings.TrimSpace(cellAt(row, iName))
+		if name == "" {
+			continue
+		}
+		key := strings.ToLower(name)
+		names[key] = name
+		if w, err := strconv.ParseFloat(cellAt(row, iWeight), 64); err == nil {
+			weights[key] = w
+		}
+	}
+	return weights, names
+}
+
+/**
+*  buildCategoryIndex maps lowercase names to their category ("simple melee", "heavy", "pack") and lists the names in CSV order
+**/
+func buildCategoryIndex(rows [][]string, iName, iCategory int) (map[string]string, []string) {
+	cats := make(map[string]string, len(rows))
+	var names []string
+	for _, row := range rows[1:] {
+		name := strings.TrimSpace(cellAt(row, iName))
+		if name == "" {
+			continue
+		}
+		names = append(names, name)
+		if c := trimLower(cellAt(row, iCategory)); c != "" {
+			cats[strings.ToLower(name)] = c
+		}
+	}
+	return cats, names
+}
+
+/**
+*  buildPriceIndex maps lowercase names to their price in cp and the bundle size the price is for
+**/
+func buildPriceIndex(rows [][]string, iName, iCost, iPer int) (map[string]int, map[string]int) {
+	costs := make(map[string]int, len(rows))
+	pers := make(map[string]int, len(rows))
+	for _, row := range rows[1:] {
+		key := strings.ToLower(strings.TrimSpace(cellAt(row, iName)))
+		cost, err := parseCost(cellAt(row, iCost))
+		if key == "" || err != nil {
+			continue
+		}
+		costs[key] = cost
+		pers[key] = max(atoiCell(row, iPer), 1)
+	}
+	return costs, pers
+}
+
+/**
+*  buildWeaponIndex maps lowercase weapon names to their damage dice and their properties ("light", "versatile (1d10)")
+**/
+func buildWeaponIndex(rows [][]string, iName, iDamage, iProps int) (map[string]string, map[string][]string) {
+	damage := make(map[string]string, len(rows))
+	props := make(map[string][]string, len(rows))
+	for _, row := range rows[1:] {
+		key := strings.ToLower(strings.TrimSpace(cellAt(row, iName)))
+		if key == "" {
+			continue
+		}
+		if d := trimLower(cellAt(row, iDamage)); d != "" {
+			damage[key] = d
+		}
+		for _, p := range splitList(cellAt(row, iProps)) {
+			props[key] = append(props[key], strings.ToLower(p))
+		}
+	}
+	return damage, props
+}
+
+/**
+*  buildArmorClassIndex maps lowercase armor names to their base AC (a shield's bonus)
+**/
+func buildArmorClassIndex(rows [][]string, iName, iAC int) map[string]int {
+	out := make(map[string]int, len(rows))
+	for _, row := range rows[1:] {
+		key := strings.ToLower(strings.TrimSpace(cellAt(row, iName)))
+		if ac, err := strconv.Atoi(strings.TrimSpace(cellAt(row, iAC))); key != "" && err == nil && ac > 0 {
+			out[key] = ac
+		}
+	}
+	return out
+}
+
 /**
 *  loadEquipmentFromCSV builds a name type index from the SRD equipment CSV
 **/
@@ -123,6 +225,11 @@ func loadEquipmentFromCSV(path string) error {
 	}
 
 	csvEquipmentTypeByName = buildNameTypeIndex(rows, iName, iType)
+	csvEquipmentWeightByName, csvEquipmentDisplayName = buildWeightIndex(rows, iName, findColumnIndex(hdr, "weight"))
+	csvEquipmentCostByName, csvEquipmentPerByName = buildPriceIndex(rows, iName, findColumnIndex(hdr, "cost"), findColumnIndex(hdr, "per"))
+	csvEquipmentCategory, csvEquipmentNames = buildCategoryIndex(rows, iName, findColumnIndex(hdr, "category"))
+	csvEquipmentDamage, csvEquipmentProperties = buildWeaponIndex(rows, iName, findColumnIndex(hdr, "damage"), findColumnIndex(hdr, "properties"))
+	csvEquipmentArmorClass = buildArmorClassIndex(rows, iName, findColumnIndex(hdr, "armor_class"))
 	return nil
 }
 
@@ -157,6 +264,61 @@ func normalizeEquipment(name string) string {
 	return n
 }
 
+/**
+*  equipmentWeight returns the weight in pounds of one unit of an item
+**/
+func equipmentWeight(name string) (float64, bool) {
+	w, ok := csvEquipmentWeightByName[normalizeEquipment(name)]
+	return w, ok
+}
+
+/**
+*  equipmentCategory returns the category of an item ("martial melee", "light", "shield"), or ""
+**/
+func equipmentCategory(name string) string {
+	return csvEquipmentCategory[normalizeEquipment(name)]
+}
+
+/**
+*  equipmentNames returns the item names in CSV order
+**/
+func equipmentNames() []string {
+	return csvEquipmentNames
+}
+
+/**
+*  equipmentPrice returns the SRD price in cp and how many items it buys ("arrow" -> 100, 20)
+**/
+func equipmentPrice(name string) (int, int, bool) {
+	key := normalizeEquipment(name)
+	cost, ok := csvEquipmentCostByName[key]
+	return cost, csvEquipmentPerByName[key], ok
+}
+
+/**
+*  equipmentDamage returns a weapon's damage dice ("1d8"), or ""
+**/
+func equipmentDamage(name string) string {
+	return csvEquipmentDamage[normalizeEquipment(name)]
+}
+
+/**
+*  equipmentProperties returns a weapon's properties ("finesse", "light", "versatile (1d10)")
+**/
+func equipmentProperties(name string) []string {
+	return csvEquipmentProperties[normalizeEquipment(name)]
+}
+
+/**
+*  equipmentDisplayName returns the CSV spelling of an item ("chain mail" -> "Chain Mail"), or the name as given
+**/
+func equipmentDisplayName(name string) string {
+	if n, ok := csvEquipmentDisplayName[normalizeEquipment(name)]; ok {
+		return n
+	}
+	return strings.TrimSpace(name)
+}
+
 /**
 *  isKnownEquipment reports whether the name exists in the CSV index
 **/
@@ -164,3 +326,42 @@ func isKnownEquipment(name string) bool {
 	_, ok := equipmentType(name)
 	return ok
 }
+
+/**
+*  CSVEquipmentAdapter answers weapon and armor lookups from the equipment CSV, without network access
+**/
+type CSVEquipmentAdapter struct{}
+
+func (a *CSVEquipmentAdapter) FetchWeaponMeta(name string) (WeaponMeta, bool) {
+	if t, _ := equipmentType(normalizeEquipment(name)); t != "weapon" {
+		return WeaponMeta{}, false
+	}
+	kind, reach, _ := strings.Cut(equipmentCategory(name), " ")
+	wm := WeaponMeta{
+		Category:    titleWords(kind),
+		DamageDice:  equipmentDamage(name),
+		WeaponRange: titleWords(reach),
+		TwoHanded:   hasWeaponProperty(name, "two-handed"),
+		Finesse:     hasWeaponProperty(name, "finesse"),
+	}
+	if strings.EqualFold(wm.WeaponRange, "melee") {
+		wm.RangeNormal = 5
+	}
+	return wm, true
+}
+
+func (a *CSVEquipmentAdapter) FetchArmorMeta(name string) (ArmorMeta, bool) {
+	ac, ok := csvEquipmentArmorClass[normalizeEquipment(name)]
+	if !ok {
+		return ArmorMeta{}, false
+	}
+	am := ArmorMeta{ArmorClass: ac}
+	switch equipmentCategory(name) {
+	case "light":
+		am.DexBonus = true
+	case "medium":
+		maxDex := 2
+		am.DexBonus, am.MaxDexBonus = true, &maxDex
+	}
+	return am, true
+}
@@ -1,6 +1,10 @@
 package main
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+)
 
 func mkChar(str, dex int, dice, wrange string, finesse bool) *Character {
 	return &Character{
@@ -50,3 +54,899 @@ func TestDamage_Bruni(t *testing.T) {
 		t.Fatalf("Bruni dmg = %q; want %q", got, want)
 	}
 }
+
+func TestPointBuyBudget(t *testing.T) {
+	s, left, err := pointBuyScores(AbilityScores{Strength: 15, Dexterity: 14, Constitution: 13, Wisdom: 10})
+	if err != nil {
+		t.Fatalf("pointBuyScores: %v", err)
+	}
+	if s.Intelligence != 8 || s.Charisma != 8 {
+		t.Fatalf("unset scores = %d/%d; want 8", s.Intelligence, s.Charisma)
+	}
+	if left != 4 {
+		t.Fatalf("points left = %d; want 4", left)
+	}
+	if _, _, err := pointBuyScores(AbilityScores{15, 15, 15, 9, 8, 8}); err == nil {
+		t.Fatal("expected budget error for 15/15/15/9")
+	}
+	if _, _, err := pointBuyScores(AbilityScores{Strength: 16}); err == nil {
+		t.Fatal("expected range error for 16")
+	}
+}
+
+func TestRollAbilityScoresSeeded(t *testing.T) {
+	a, ra, err := rollAbilityScores(42, "order", nil)
+	if err != nil {
+		t.Fatalf("rollAbilityScores: %v", err)
+	}
+	b, _, _ := rollAbilityScores(42, "order", nil)
+	if a != b {
+		t.Fatalf("same seed gave %+v and %+v", a, b)
+	}
+	for i, dice := range ra.Dice {
+		if len(dice) != 4 || ra.Totals[i] < 3 || ra.Totals[i] > 18 {
+			t.Fatalf("roll %d = %v -> %d", i, dice, ra.Totals[i])
+		}
+	}
+	w, _, _ := rollAbilityScores(42, "class", classPriority("wizard"))
+	arr := abilityArray(w)
+	for i, v := range arr {
+		if v > w.Intelligence {
+			t.Fatalf("wizard %s %d beats INT %d", abilityShortNames[i], v, w.Intelligence)
+		}
+	}
+}
+
+func TestClassCatalogDrivesHelpers(t *testing.T) {
+	if got := casterType("druid"); got != "full" {
+		t.Fatalf("casterType(druid) = %q; want full", got)
+	}
+	if got := classSkillCount("bard"); got != 3 {
+		t.Fatalf("classSkillCount(bard) = %d; want 3", got)
+	}
+	if got := cantripsKnown("sorcerer", 10); got != 6 {
+		t.Fatalf("cantripsKnown(sorcerer, 10) = %d; want 6", got)
+	}
+	if got := spellcastingAbilityForClass("paladin"); got != "charisma" {
+		t.Fatalf("spellcastingAbilityForClass(paladin) = %q; want charisma", got)
+	}
+	if _, err := resolveClass("wizzard"); err == nil {
+		t.Fatal("expected unknown class error")
+	}
+}
+
+func TestMaxHPAverageTracksCon(t *testing.T) {
+	c := &Character{Class: "fighter", Level: 3, AbilityScores: AbilityScores{Constitution: 14}}
+	if err := gainHitDice(c, "avg", 0); err != nil {
+		t.Fatalf("gainHitDice: %v", err)
+	}
+	recalcHP(c)
+	if c.MaxHP != 28 || c.CurrentHP != 28 {
+		t.Fatalf("fighter 3 HP = %d/%d; want 28/28", c.CurrentHP, c.MaxHP)
+	}
+	applyDamage(c, 5)
+	c.AbilityScores.Constitution = 16
+	recalcHP(c)
+	if c.MaxHP != 31 || c.CurrentHP != 26 {
+		t.Fatalf("after CON bump HP = %d/%d; want 26/31", c.CurrentHP, c.MaxHP)
+	}
+}
+
+func TestSavingThrowsUseClassProficiencies(t *testing.T) {
+	c := &Character{Class: "wizard", ProficiencyBonus: 2, AbilityScores: AbilityScores{10, 14, 12, 16, 13, 8}}
+	want := map[string]int{"str": 0, "dex": 2, "con": 1, "int": 5, "wis": 3, "cha": -1}
+	for _, st := range computeSavingThrows(c) {
+		if st.Modifier != want[st.Ability] {
+			t.Fatalf("%s save = %+d; want %+d", st.Ability, st.Modifier, want[st.Ability])
+		}
+	}
+}
+
+func TestSkillChoicesValidated(t *testing.T) {
+	if _, err := finalSkills("fighter", "criminal", []string{"arcana", "athletics"}); err == nil {
+		t.Fatal("expected arcana to be rejected for a fighter")
+	}
+	got, err := finalSkills("fighter", "soldier", []string{"arcana", "perception"})
+	if err != nil {
+		t.Fatalf("overlap replacement: %v", err)
+	}
+	if strings.Join(got, ",") != "arcana,athletics,intimidation,perception" {
+		t.Fatalf("skills = %v", got)
+	}
+	_, err = finalSkills("wizard", "sage", []string{"investigation", "stelth"})
+	var ve *ValidationError
+	if !errors.As(err, &ve) || ve.Fields[0].Field != "skills[1]" {
+		t.Fatalf("want field error on skills[1], got %v", err)
+	}
+	// sage grants arcana and history; a wizard naming one of them must pick a replacement
+	_, err = finalSkills("wizard", "sage", []string{"investigation", "arcana", "medicine"})
+	if err != nil {
+		t.Fatalf("an extra pick should cover the repeat: %v", err)
+	}
+	_, err = finalSkills("wizard", "sage", []string{"history", "investigation"})
+	if !errors.As(err, &ve) || ve.Fields[0].Field != "skills[0]" || !strings.Contains(ve.Fields[0].Message, "history is already granted") {
+		t.Fatalf("want the history repeat reported on skills[0], got %v", err)
+	}
+}
+
+func TestSkillTableAndExpertise(t *testing.T) {
+	c := &Character{Class: "rogue", Level: 1, ProficiencyBonus: 2,
+		AbilityScores: AbilityScores{8, 16, 12, 10, 14, 13},
+		Skills:        []string{"perception", "stealth"},
+		Expertise:     []string{"stealth"},
+	}
+	table := computeSkillTable(c)
+	if len(table) != 18 {
+		t.Fatalf("skill table has %d rows; want 18", len(table))
+	}
+	want := map[string]SkillModifier{
+		"stealth":    {Skill: "stealth", Ability: "dex", Modifier: 7, Proficient: true, Expertise: true},
+		"perception": {Skill: "perception", Ability: "wis", Modifier: 4, Proficient: true},
+		"athletics":  {Skill: "athletics", Ability: "str", Modifier: -1},
+		"arcana":     {Skill: "arcana", Ability: "int", Modifier: 0},
+		"persuasion": {Skill: "persuasion", Ability: "cha", Modifier: 1},
+	}
+	for _, sm := range table {
+		if w, ok := want[sm.Skill]; ok && sm != w {
+			t.Fatalf("%s = %+v; want %+v", sm.Skill, sm, w)
+		}
+	}
+	slots := []struct {
+		class string
+		level int
+		want  int
+	}{
+		{"rogue", 1, 2}, {"rogue", 5, 2}, {"rogue", 6, 4}, {"bard", 2, 0}, {"bard", 3, 2}, {"bard", 10, 4}, {"fighter", 20, 0},
+	}
+	for _, tc := range slots {
+		if got := expertiseSlots(tc.class, tc.level); got != tc.want {
+			t.Fatalf("expertiseSlots(%s, %d) = %d; want %d", tc.class, tc.level, got, tc.want)
+		}
+	}
+	if got, err := validateExpertise(c, []string{"Stealth", "perception"}); err != nil || strings.Join(got, ",") != "perception,stealth" {
+		t.Fatalf("validateExpertise = %v, %v", got, err)
+	}
+	for _, bad := range [][]string{{"athletics"}, {"stelth"}} {
+		if _, err := validateExpertise(c, bad); err == nil {
+			t.Fatalf("expertise %v should be refused", bad)
+		}
+	}
+	c.Skills = append(c.Skills, "acrobatics")
+	if _, err := validateExpertise(c, []string{"stealth", "perception", "acrobatics"}); err == nil {
+		t.Fatal("a level 1 rogue should be refused a third expertise")
+	}
+}
+
+func TestBackgroundResolveAndApply(t *testing.T) {
+	if bg, err := resolveBackground(""); err != nil || bg.Name != "Acolyte" {
+		t.Fatalf("default background = %+v, %v; want Acolyte", bg, err)
+	}
+	if bg, err := resolveBackground("  SOLDIER "); err != nil || bg.Name != "Soldier" {
+		t.Fatalf("resolveBackground(SOLDIER) = %+v, %v", bg, err)
+	}
+	if _, err := resolveBackground("acolite"); err == nil || !strings.Contains(err.Error(), `did you mean "acolyte"`) {
+		t.Fatalf("expected a suggestion for acolite, got %v", err)
+	}
+	c, err := buildCharacterFromRequest(createRequest{Name: "B", Class: "wizard", Background: "acolyte", Skills: []string{"arcana", "history"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !containsString(c.Skills, "insight") || !containsString(c.Skills, "religion") || c.BackgroundFeature != "Shelter of the Faithful" {
+		t.Fatalf("acolyte wizard: skills %v, feature %q", c.Skills, c.BackgroundFeature)
+	}
+}
+
+func TestRaceResolveAndHalfElfBonuses(t *testing.T) {
+	if _, err := resolveRace("hil dwarf"); err == nil || !strings.Contains(err.Error(), `did you mean "hill dwarf"`) {
+		t.Fatalf("expected a suggestion for hil dwarf, got %v", err)
+	}
+	if r, err := resolveRace("Wood Elf"); err != nil || r.Speed != 35 {
+		t.Fatalf("non-SRD subrace wood elf = %+v, %v", r, err)
+	}
+	he, err := resolveRace("half-elf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, picked, err := raceBonusesFor(he, []string{"dex", "con"}, nil)
+	if err != nil || got != (AbilityScores{Dexterity: 1, Constitution: 1, Charisma: 2}) || strings.Join(picked, ",") != "dex,con" {
+		t.Fatalf("half-elf dex,con = %+v %v, %v", got, picked, err)
+	}
+	if _, picked, err := raceBonusesFor(he, nil, []string{"cha", "wis", "int"}); err != nil || strings.Join(picked, ",") != "wis,int" {
+		t.Fatalf("half-elf priority picks = %v, %v; want wis,int", picked, err)
+	}
+	for _, bad := range [][]string{{"dex", "dex"}, {"cha", "dex"}, {"dex"}, {"dex", "con", "wis"}} {
+		if _, _, err := raceBonusesFor(he, bad, nil); err == nil {
+			t.Fatalf("half-elf bonus %v should be refused", bad)
+		}
+	}
+}
+
+func TestLevelUpRecomputesLevelStats(t *testing.T) {
+	c := Character{Name: "T", Class: "cleric", Level: 1, ProficiencyBonus: 2, AbilityScores: AbilityScores{Constitution: 12}}
+	_ = gainHitDice(&c, "avg", 1)
+	recalcHP(&c)
+	up, res, err := levelUp(c, 5, "", 0, LevelUpChoices{})
+	if err != nil {
+		t.Fatalf("levelUp: %v", err)
+	}
+	if up.ProficiencyBonus != 3 || up.MaxHP != 33 || res.HPGained != 24 {
+		t.Fatalf("level 5 cleric: prof %d, HP %d (+%d); want 3, 33 (+24)", up.ProficiencyBonus, up.MaxHP, res.HPGained)
+	}
+	if up.Spellcasting == nil || up.Spellcasting.SlotsByLevel[3] != 2 {
+		t.Fatalf("level 5 cleric slots = %v", up.Spellcasting)
+	}
+	if c.Level != 1 {
+		t.Fatal("levelUp modified the original character")
+	}
+	if _, _, err := levelUp(up, 4, "", 0, LevelUpChoices{}); err == nil {
+		t.Fatal("expected error levelling down")
+	}
+}
+
+func TestImprovementsRebuildScores(t *testing.T) {
+	c := &Character{Name: "T", Race: "mountain dwarf", Class: "fighter", Level: 6, AbilityScores: AbilityScores{17, 12, 16, 8, 10, 8}}
+	setBaseScores(c)
+	if c.BaseAbilityScores.Strength != 15 || c.BaseAbilityScores.Constitution != 14 {
+		t.Fatalf("base scores = %+v", c.BaseAbilityScores)
+	}
+	if _, err := applyImprovement(c, "", 0, []string{"str:2"}, ""); err != nil {
+		t.Fatalf("asi: %v", err)
+	}
+	if _, err := applyImprovement(c, "", 0, []string{"str:2"}, ""); err == nil {
+		t.Fatal("expected STR 19 +2 to be rejected")
+	}
+	imp, err := applyImprovement(c, "", 0, nil, "grappler")
+	if err != nil || imp.Class != "fighter" || imp.Level != 6 {
+		t.Fatalf("feat at level 6: %+v, %v", imp, err)
+	}
+	if c.AbilityScores.Strength != 19 || len(openASILevels(c)) != 0 {
+		t.Fatalf("STR %d, open ASIs %v", c.AbilityScores.Strength, openASILevels(c))
+	}
+}
+
+func TestMulticlassCombinesSpellSlots(t *testing.T) {
+	c := Character{Name: "T", Class: "wizard", Level: 3, AbilityScores: AbilityScores{10, 12, 12, 16, 10, 14}}
+	var ve *ValidationError
+	if _, _, err := levelUp(c, 4, "", 1, LevelUpChoices{Class: "fighter"}); !errors.As(err, &ve) || ve.Fields[0].Field != "class" {
+		t.Fatalf("expected STR/DEX 13 prerequisite for fighter on class, got %v", err)
+	}
+	if _, _, err := levelUp(c, 3, "", 1, LevelUpChoices{Class: "sorcerer"}); !errors.As(err, &ve) || ve.Fields[0].Field != "to" {
+		t.Fatalf("expected the target level error on to, got %v", err)
+	}
+	up, _, err := levelUp(c, 5, "", 1, LevelUpChoices{Class: "sorcerer"})
+	if err != nil {
+		t.Fatalf("levelUp: %v", err)
+	}
+	if formatClassLevels(&up) != "wizard 3 / sorcerer 2" || up.ProficiencyBonus != 3 {
+		t.Fatalf("classes %q, prof %d", formatClassLevels(&up), up.ProficiencyBonus)
+	}
+	if got := up.Spellcasting.SlotsByLevel; got[1] != 4 || got[2] != 3 || got[3] != 2 {
+		t.Fatalf("wizard 3 / sorcerer 2 slots = %v; want caster level 5", got)
+	}
+	up, _, err = levelUp(up, 6, "", 1, LevelUpChoices{Class: "warlock"})
+	if err != nil {
+		t.Fatalf("levelUp warlock: %v", err)
+	}
+	if up.Spellcasting.PactSlots[1] != 1 || up.Spellcasting.SlotsByLevel[3] != 2 {
+		t.Fatalf("pact %v, slots %v", up.Spellcasting.PactSlots, up.Spellcasting.SlotsByLevel)
+	}
+}
+
+func TestSubclassAddsFeaturesAndDomainSpells(t *testing.T) {
+	c := Character{Name: "T", Class: "cleric", Level: 1, Spellcasting: &Spellcasting{}}
+	if _, err := chooseSubclass(&c, "", "life domain"); err != nil {
+		t.Fatalf("chooseSubclass: %v", err)
+	}
+	up, res, err := levelUp(c, 3, "avg", 1, LevelUpChoices{})
+	if err != nil {
+		t.Fatalf("levelUp: %v", err)
+	}
+	found := false
+	for _, f := range res.Features {
+		found = found || f.Name == "Channel Divinity: Preserve Life"
+	}
+	if !found {
+		t.Fatalf("features %v missing the level 2 Life Domain feature", res.Features)
+	}
+	always := 0
+	for _, s := range up.Spellcasting.Spells {
+		if s.AlwaysPrepared && s.Prepared {
+			always++
+		}
+	}
+	if always != 4 {
+		t.Fatalf("cleric 3 Life Domain always-prepared spells = %d; want 4", always)
+	}
+	fighter := Character{Name: "F", Class: "fighter", Level: 2}
+	if _, err := chooseSubclass(&fighter, "", "champion"); err == nil {
+		t.Fatal("expected champion to need fighter level 3")
+	}
+}
+
+func TestClassFeatureScaling(t *testing.T) {
+	c := &Character{Class: "rogue", Level: 7}
+	values := map[string]string{}
+	for _, f := range characterFeatures(c) {
+		values[f.Name] = formatFeatureValues(f.Values)
+	}
+	if values["Sneak Attack"] != "dice 4d6" {
+		t.Fatalf("rogue 7 sneak attack = %q; want dice 4d6", values["Sneak Attack"])
+	}
+	p := &Character{Class: "paladin", Level: 4, AbilityScores: AbilityScores{Charisma: 14}}
+	for _, f := range characterFeatures(p) {
+		if f.Name == "Lay on Hands" && formatFeatureValues(f.Values) != "pool 20" {
+			t.Fatalf("paladin 4 lay on hands = %v", f.Values)
+		}
+		if f.Name == "Divine Sense" && formatFeatureValues(f.Values) != "uses 3" {
+			t.Fatalf("paladin divine sense with CHA 14 = %v", f.Values)
+		}
+	}
+	// Wild Shape hours round down, Arcane Recovery rounds up
+	d := &Character{Class: "druid", Level: 5}
+	for _, v := range featureValues(d, "druid", "Wild Shape", 5) {
+		if v.Label == "hours" && v.Value != "2" {
+			t.Fatalf("druid 5 wild shape hours = %q; want 2", v.Value)
+		}
+	}
+	w := &Character{Class: "wizard", Level: 5}
+	for _, v := range featureValues(w, "wizard", "Arcane Recovery", 5) {
+		if v.Label == "slot levels" && v.Value != "3" {
+			t.Fatalf("wizard 5 arcane recovery = %q; want 3", v.Value)
+		}
+	}
+}
+
+func TestXPThresholdsAndSplit(t *testing.T) {
+	if got := levelForXP(899); got != 2 {
+		t.Fatalf("levelForXP(899) = %d; want 2", got)
+	}
+	if got := levelForXP(900); got != 3 {
+		t.Fatalf("levelForXP(900) = %d; want 3", got)
+	}
+	if got := splitXP(100, 3); got[0] != 34 || got[1] != 33 || got[2] != 33 {
+		t.Fatalf("splitXP(100, 3) = %v; want [34 33 33]", got)
+	}
+	chars := []Character{
+		{Name: "A", Class: "fighter", Level: 1, HPMethod: "avg", AbilityScores: AbilityScores{Constitution: 10}},
+		{Name: "B", Class: "fighter", Level: 1, HPMethod: "avg", AbilityScores: AbilityScores{Constitution: 10}},
+	}
+	out, awards, err := awardXPSplit(chars, 600, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chars[0].XP != 0 || out[1].XP != 300 || awards[1].ReadyLevel != 2 || out[1].Level != 1 {
+		t.Fatalf("split award = %+v", awards)
+	}
+	out, awards, err = awardXPSplit(out[:1], 600, true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out[0].Level != 3 || awards[0].LevelUp == nil {
+		t.Fatalf("auto level up after 900 xp: level %d, award %+v", out[0].Level, awards[0])
+	}
+	if _, _, err := awardXPSplit(chars, 1, false, ""); err == nil {
+		t.Fatal("expected 1 xp split over 2 characters to fail")
+	}
+}
+
+func TestGenerateCharacterIsLegalAndSeeded(t *testing.T) {
+	for seed := int64(1); seed <= 30; seed++ {
+		c, err := generateCharacter(GenerateOptions{Seed: seed, Level: 4})
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		if _, ok := lookupClass(c.Class); !ok {
+			t.Fatalf("seed %d: unknown class %q", seed, c.Class)
+		}
+		if len(openASILevels(&c)) != 0 {
+			t.Fatalf("seed %d: %s has unspent ASIs", seed, c.Name)
+		}
+		if got := len(c.Skills) - len(defaultBackgroundSkills(c.Background)); got != classSkillCount(c.Class) {
+			t.Fatalf("seed %d: %s %s has %d class skills; want %d", seed, c.Class, c.Background, got, classSkillCount(c.Class))
+		}
+		if c.MaxHP < 4 {
+			t.Fatalf("seed %d: max HP %d", seed, c.MaxHP)
+		}
+		again, _ := generateCharacter(GenerateOptions{Seed: seed, Level: 4})
+		if again.Name != c.Name || again.AbilityScores != c.AbilityScores || again.Equipment != c.Equipment {
+			t.Fatalf("seed %d is not reproducible", seed)
+		}
+	}
+	w, err := generateCharacter(GenerateOptions{Seed: 3, Class: "wizard", Level: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Spellcasting == nil || len(w.Spellcasting.Spells) == 0 {
+		t.Fatal("generated wizard has no spells")
+	}
+	if _, err := generateCharacter(GenerateOptions{Seed: 1, Class: "pirate"}); err == nil {
+		t.Fatal("expected unknown class to fail")
+	}
+	saved := csvClasses
+	csvClasses = map[string]ClassInfo{}
+	_, err = generateCharacter(GenerateOptions{Seed: 1})
+	csvClasses = saved
+	var ve *ValidationError
+	if !errors.As(err, &ve) || ve.Fields[0].Field != "class" {
+		t.Fatalf("expected a class field error with no classes loaded, got %v", err)
+	}
+}
+
+func TestNameGeneratorByRace(t *testing.T) {
+	names, err := randomNames(newRoller(42), "hill dwarf", "female", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := randomNames(newRoller(42), "hill dwarf", "female", 10)
+	seen := map[string]bool{}
+	for i, n := range names {
+		if seen[n] {
+			t.Fatalf("duplicate name %q in %v", n, names)
+		}
+		seen[n] = true
+		if n != again[i] {
+			t.Fatalf("seed 42 gave %q then %q", n, again[i])
+		}
+		given, family, ok := strings.Cut(n, " ")
+		if !ok || !containsString(csvNameLists["dwarf"].Family, family) || len(given) < 3 {
+			t.Fatalf("dwarf name %q should be a given name plus a clan name", n)
+		}
+	}
+	orc, err := randomName(newRoller(1), "half-orc", "")
+	if err != nil || strings.Contains(orc, " ") {
+		t.Fatalf("half-orc name = %q, %v; want a single given name", orc, err)
+	}
+	if _, err := randomNames(newRoller(1), "elf", "robot", 1); err == nil {
+		t.Fatal("expected an unknown gender to fail")
+	}
+}
+
+func TestPersonaRollAndOverrides(t *testing.T) {
+	c := &Character{Name: "P", Race: "mountain dwarf", Background: "acolyte"}
+	if err := applyPersona(c, Persona{Eyes: "violet"}, true, 11); err != nil {
+		t.Fatal(err)
+	}
+	if c.PersonaSeed != 11 {
+		t.Fatalf("persona seed = %d; want the roll's seed 11 kept", c.PersonaSeed)
+	}
+	p := c.Persona
+	if p.Height < 50 || p.Height > 56 || p.Weight < 130+2*2 || p.Weight > 130+8*12 {
+		t.Fatalf("mountain dwarf height %d, weight %d out of range", p.Height, p.Weight)
+	}
+	if p.Age < 50 || len(p.PersonalityTraits) != 2 || p.Ideal == "" || p.Bond == "" || p.Flaw == "" || p.Eyes != "violet" {
+		t.Fatalf("rolled persona incomplete: %+v", p)
+	}
+	if !containsString(alignments, p.Alignment) {
+		t.Fatalf("rolled alignment %q", p.Alignment)
+	}
+	if err := applyPersona(c, Persona{Alignment: "CE"}, false, 0); err != nil || c.Alignment != "chaotic evil" || c.Ideal != p.Ideal {
+		t.Fatalf("alignment override: %v, %+v", err, c.Persona)
+	}
+	if h, err := parseHeight(`5'10"`); err != nil || h != 70 || formatHeight(h) != `5'10"` {
+		t.Fatalf("parseHeight(5'10\") = %d, %v", h, err)
+	}
+	if err := applyPersona(c, Persona{Alignment: "sneaky"}, false, 0); err == nil {
+		t.Fatal("expected an unknown alignment to fail")
+	}
+}
+
+func TestLanguagesAndToolsFromRaceAndBackground(t *testing.T) {
+	c := &Character{Name: "L", Race: "human", Class: "rogue", Background: "criminal"}
+	if err := assignProficiencies(c, []string{"elvish"}, []string{"dice set", "lute"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(c.Languages, ",") != "Common,Elvish" {
+		t.Fatalf("languages = %v; want Common, Elvish", c.Languages)
+	}
+	// rogue and criminal both grant thieves' tools; the duplicate becomes a free pick
+	if strings.Join(c.Tools, ",") != "Thieves' Tools,Dice Set,Lute" {
+		t.Fatalf("tools = %v", c.Tools)
+	}
+	if err := assignProficiencies(c, []string{"druidic"}, nil, nil); err == nil {
+		t.Fatal("expected a secret language to be rejected as a free pick")
+	}
+	var ve *ValidationError
+	err := assignProficiencies(c, nil, []string{"lute", "lyre"}, nil)
+	if !errors.As(err, &ve) || ve.Fields[0].Field != "tools[1]" {
+		t.Fatalf("expected a field error on tools[1], got %v", err)
+	}
+	d := &Character{Name: "D", Race: "hill dwarf", Class: "fighter", Background: "soldier"}
+	if err := assignProficiencies(d, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Tools) != 3 || !containsString(d.Languages, "Dwarvish") {
+		t.Fatalf("dwarf soldier: languages %v, tools %v", d.Languages, d.Tools)
+	}
+}
+
+func TestInventoryAndEncumbrance(t *testing.T) {
+	c := &Character{Name: "I", Race: "human", AbilityScores: AbilityScores{Strength: 10}, Equipment: Equipment{Armor: "chain mail"}}
+	ensureEquipmentInInventory(c)
+	if len(c.Inventory) != 1 || c.Inventory[0].Name != "Chain Mail" || c.Inventory[0].Weight != 55 || !c.Inventory[0].Equipped {
+		t.Fatalf("equipped armor not in inventory: %+v", c.Inventory)
+	}
+	if _, err := addItem(c, Item{Name: "arrow", Quantity: 20, Weight: -1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := addItem(c, Item{Name: "Arrow", Quantity: 10, Weight: -1}); err != nil || len(c.Inventory) != 2 || c.Inventory[1].Quantity != 30 {
+		t.Fatalf("arrows did not stack: %v, %+v", err, c.Inventory)
+	}
+	e := encumbranceFor(c, false)
+	if e.Carried != 56.5 || e.Capacity != 150 || e.PushDragLift != 300 || e.Status != statusNormal || e.Speed != 30 {
+		t.Fatalf("encumbrance = %+v", e)
+	}
+	if e := encumbranceFor(c, true); e.Status != statusEncumbered || e.Speed != 20 {
+		t.Fatalf("variant encumbrance = %+v; want encumbered at 20 ft", e)
+	}
+	if _, err := removeItem(c, "arrow", 31); err == nil {
+		t.Fatal("expected removing more arrows than carried to fail")
+	}
+	if _, err := removeItem(c, "chain mail", 0); err != nil || c.Equipment.Armor != "" || len(c.Inventory) != 1 {
+		t.Fatalf("removing armor: %v, armor %q, %+v", err, c.Equipment.Armor, c.Inventory)
+	}
+	for _, ring := range []string{"Ring A", "Ring B", "Ring C"} {
+		if _, err := addItem(c, Item{Name: ring, Quantity: 1, Attuned: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := addItem(c, Item{Name: "Ring D", Quantity: 1, Attuned: true}); err == nil {
+		t.Fatal("expected a fourth attunement to fail")
+	}
+}
+
+func TestPurseChangeAndTrading(t *testing.T) {
+	p, err := spendCoins(Purse{CP: 3, GP: 2}, 125)
+	if err != nil || p != (Purse{SP: 7, CP: 8}) {
+		t.Fatalf("spend 1 gp 2 sp 5 cp from 2 gp 3 cp = %+v, %v; want 7 sp 8 cp", p, err)
+	}
+	if _, err := spendCoins(Purse{SP: 9}, 100); err == nil {
+		t.Fatal("expected spending more than the purse holds to fail")
+	}
+	if in, err := parseCoins("2gp 5 sp, 1 pp"); err != nil || purseValue(in) != 1250 {
+		t.Fatalf("parseCoins = %+v, %v", in, err)
+	}
+	c := &Character{Name: "T", Class: "fighter", AbilityScores: AbilityScores{Strength: 10}}
+	if gp, err := grantStartingGold(c, "avg", nil); err != nil || gp != 125 {
+		t.Fatalf("fighter average starting gold = %d, %v; want 125", gp, err)
+	}
+	if cost, err := buyItem(c, "arrow", 30); err != nil || cost != 150 {
+		t.Fatalf("30 arrows cost %d cp, %v; want 150", cost, err)
+	}
+	if _, err := buyItem(c, "plate armor", 1); err == nil {
+		t.Fatal("expected plate armor to be too expensive")
+	}
+	if price, err := sellItem(c, "arrow", 10); err != nil || price != 25 || c.Inventory[0].Quantity != 20 {
+		t.Fatalf("selling 10 arrows: %d cp, %v, %+v", price, err, c.Inventory)
+	}
+	if purseValue(c.Purse) != 12500-150+25 {
+		t.Fatalf("purse worth %d cp after trading", purseValue(c.Purse))
+	}
+}
+
+func TestStartingEquipmentPackages(t *testing.T) {
+	c := &Character{Name: "K", Class: "barbarian", Background: "soldier"}
+	if err := applyStartingEquipment(c, []string{"battleaxe", "b"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if c.Equipment.Weapon != "battleaxe" || c.Purse.GP != 10 {
+		t.Fatalf("weapon %q, purse %+v; want battleaxe and the soldier's 10 gp", c.Equipment.Weapon, c.Purse)
+	}
+	for _, want := range []string{"Explorer's Pack", "Javelin", "Dice Set", "Club"} {
+		if findItem(c.Inventory, want, "", false) < 0 {
+			t.Fatalf("%s missing from %+v", want, c.Inventory)
+		}
+	}
+	if i := findItem(c.Inventory, "javelin", "", false); c.Inventory[i].Quantity != 4 {
+		t.Fatalf("javelins = %d; want 4", c.Inventory[i].Quantity)
+	}
+	f := &Character{Name: "F", Class: "fighter", Background: "acolyte"}
+	if err := applyStartingEquipment(f, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if f.Equipment.Armor != "chain mail" || f.Equipment.Shield != "shield" || f.Equipment.Weapon == "" {
+		t.Fatalf("default fighter equipment = %+v", f.Equipment)
+	}
+	var ve *ValidationError
+	err := applyStartingEquipment(&Character{Name: "W", Class: "wizard"}, []string{"a", "greatsword"}, nil)
+	if !errors.As(err, &ve) || ve.Fields[0].Field != "equipment[1]" {
+		t.Fatalf("expected a field error on equipment[1], got %v", err)
+	}
+}
+
+func TestEquipReplaceUnequipAndSwapHands(t *testing.T) {
+	c := &Character{Name: "E", Equipment: Equipment{Weapon: "battleaxe", WeaponInfo: WeaponMeta{DamageDice: "1d8"}}}
+	ensureEquipmentInInventory(c)
+	var ve *ValidationError
+	if _, err := equipSlot(c, slotMain, "longsword", false); !errors.As(err, &ve) || ve.Fields[0].Field != "weapon" {
+		t.Fatalf("expected an occupied-slot error on weapon, got %v", err)
+	}
+	old, err := equipSlot(c, slotMain, "longsword", true)
+	if err != nil || len(old) != 1 || old[0] != "battleaxe" || c.Equipment.Weapon != "longsword" || c.Equipment.WeaponInfo.DamageDice != "" {
+		t.Fatalf("replace: old %q, err %v, equipment %+v", old, err, c.Equipment)
+	}
+	if i := findItem(c.Inventory, "battleaxe", "", false); i < 0 || c.Inventory[i].Equipped {
+		t.Fatalf("displaced battleaxe should be carried, not equipped: %+v", c.Inventory)
+	}
+	if _, err := equipSlot(c, slotOff, "dagger", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := swapHands(c); err != nil || c.Equipment.Weapon != "dagger" || c.Equipment.OffHand != "longsword" {
+		t.Fatalf("swap: err %v, equipment %+v", err, c.Equipment)
+	}
+	if old, err := unequipSlot(c, slotOff); err != nil || old != "longsword" || c.Equipment.OffHand != "" {
+		t.Fatalf("unequip: old %q, err %v", old, err)
+	}
+	if i := findItem(c.Inventory, "longsword", "", false); i < 0 || c.Inventory[i].Equipped {
+		t.Fatalf("unequipped longsword should stay in the inventory: %+v", c.Inventory)
+	}
+	if _, err := unequipSlot(c, slotShield); err == nil {
+		t.Fatal("expected an error unequipping an empty slot")
+	}
+	if s, err := parseSlot("Off"); err != nil || s != slotOff {
+		t.Fatalf("parseSlot(Off) = %q, %v", s, err)
+	}
+}
+
+func TestHandOccupancyRules(t *testing.T) {
+	c := &Character{Name: "H", AbilityScores: AbilityScores{Strength: 16}, Equipment: Equipment{Weapon: "longsword"}}
+	if got, want := computeWeaponDamageString(c), "1d10 + 3"; got != want {
+		t.Fatalf("versatile two-handed damage = %q; want %q", got, want)
+	}
+	if _, err := equipSlot(c, slotShield, "shield", false); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := computeWeaponDamageString(c), "1d8 + 3"; got != want {
+		t.Fatalf("versatile one-handed damage = %q; want %q", got, want)
+	}
+	var ve *ValidationError
+	if _, err := equipSlot(c, slotMain, "greataxe", true); err != nil || c.Equipment.Shield != "" {
+		t.Fatalf("replacing with a greataxe should free the shield: err %v, equipment %+v", err, c.Equipment)
+	}
+	if _, err := equipSlot(c, slotOff, "dagger", false); !errors.As(err, &ve) || ve.Fields[0].Field != "offhand" {
+		t.Fatalf("expected the greataxe to block the off hand, got %v", err)
+	}
+	if _, err := equipSlot(c, slotOff, "maul", true); err == nil {
+		t.Fatal("expected a two-handed weapon to be refused in the off hand")
+	}
+	if err := checkHands(Equipment{Weapon: "shortsword", OffHand: "dagger", Shield: "shield"}); !errors.As(err, &ve) || ve.Fields[0].Field != "shield" {
+		t.Fatalf("expected a shield clash with the off-hand dagger, got %v", err)
+	}
+	if w := handWarnings(Equipment{Weapon: "shortsword", OffHand: "dagger"}); len(w) != 0 {
+		t.Fatalf("two light weapons should not warn: %v", w)
+	}
+	if w := handWarnings(Equipment{Weapon: "longsword", OffHand: "dagger"}); len(w) != 1 || !strings.Contains(w[0], "longsword") {
+		t.Fatalf("expected a light-weapon warning about the longsword, got %v", w)
+	}
+}
+
+func TestKeepProgressOnResave(t *testing.T) {
+	old := Character{Name: "K", Class: "Fighter", Level: 5, XP: 6500, Race: "Human",
+		Classes:           []ClassLevel{{Class: "Fighter", Level: 4}, {Class: "Rogue", Level: 1}},
+		Subclasses:        map[string]string{"fighter": "Champion"},
+		Improvements:      []Improvement{{Class: "Fighter", Level: 4, Increases: AbilityScores{Strength: 2}}},
+		AbilityScores:     AbilityScores{Strength: 18, Dexterity: 12, Constitution: 14, Intelligence: 10, Wisdom: 10, Charisma: 8},
+		BaseAbilityScores: AbilityScores{Strength: 15, Dexterity: 11, Constitution: 13, Intelligence: 9, Wisdom: 9, Charisma: 7},
+		HitDieRolls:       []int{10, 6, 7, 5, 4}, HPMethod: "roll", MaxHP: 42, CurrentHP: 30,
+		Skills: []string{"athletics", "stealth"}, Expertise: []string{"stealth"},
+		Equipment: Equipment{Weapon: "longsword", Armor: "chain mail"},
+		Inventory: []Item{{Name: "Longsword", Quantity: 1, Equipped: true}, {Name: "Chain Mail", Quantity: 1, Equipped: true}, {Name: "Rope", Quantity: 1}},
+		Purse:     Purse{GP: 42}, Languages: []string{"Common", "Orc"}, Tools: []string{"Dice set"},
+		Spellcasting: &Spellcasting{},
+	}
+	resaved := func(edit func(c *Character)) Character {
+		c := Character{Name: old.Name, Class: old.Class, Level: old.Level, Race: old.Race, AbilityScores: old.AbilityScores, Skills: old.Skills}
+		edit(&c)
+		return c
+	}
+
+	c := resaved(func(c *Character) { c.Level = 6 })
+	if err := keepProgress(&c, old, createRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if c.Equipment.Weapon != "longsword" || c.Spellcasting != nil {
+		t.Fatalf("a level change should keep gear but not class-bound progress: %+v", c)
+	}
+	if err := keepProgress(&c, old, createRequest{StartingGold: "roll"}); err == nil {
+		t.Fatal("expected starting gold to be refused on an update")
+	}
+
+	c = resaved(func(c *Character) {
+		c.AbilityScores.Dexterity = 14
+		c.Skills = []string{"athletics"}
+		c.Equipment.Shield = "shield"
+	})
+	if err := keepProgress(&c, old, createRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if c.Equipment.Weapon != "longsword" || c.Equipment.Armor != "chain mail" || c.Equipment.Shield != "shield" || c.Spellcasting == nil {
+		t.Fatalf("re-save should keep the stored slots and spells and equip the sent shield: %+v", c)
+	}
+	if len(c.Expertise) != 0 {
+		t.Fatalf("expertise in a dropped skill should not be kept: %v", c.Expertise)
+	}
+	c = resaved(func(c *Character) {})
+	if err := keepProgress(&c, old, createRequest{}); err != nil || len(c.Expertise) != 1 {
+		t.Fatalf("re-save should keep expertise in a still-proficient skill: %v, %v", c.Expertise, err)
+	}
+	if len(c.HitDieRolls) != 5 || c.MaxHP-c.CurrentHP != 12 {
+		t.Fatalf("re-save should keep the rolled hit dice and damage taken: rolls %v, hp %d/%d", c.HitDieRolls, c.CurrentHP, c.MaxHP)
+	}
+	c = resaved(func(c *Character) { c.AbilityScores.Dexterity = 14 })
+	if err := keepProgress(&c, old, createRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Improvements) != 1 || c.BaseAbilityScores.Dexterity != 13 || c.BaseAbilityScores.Strength != 15 {
+		t.Fatalf("re-save should keep improvements and move the base by the edit: %v, base %+v", c.Improvements, c.BaseAbilityScores)
+	}
+	if len(c.Classes) != 2 {
+		t.Fatalf("re-save should keep multiclass levels: %v", c.Classes)
+	}
+	if c.Subclasses["fighter"] != "Champion" {
+		t.Fatalf("re-save should keep the subclass: %v", c.Subclasses)
+	}
+	if c.XP != 6500 {
+		t.Fatalf("re-save without xp should keep the stored XP, got %d", c.XP)
+	}
+	zero := 0
+	c = resaved(func(c *Character) {})
+	if err := keepProgress(&c, old, createRequest{XP: &zero}); err != nil || c.XP != 0 {
+		t.Fatalf("re-save with xp 0 should reset XP, got %d, %v", c.XP, err)
+	}
+	if len(c.Languages) != 2 || len(c.Tools) != 1 {
+		t.Fatalf("re-save should keep languages and tools: %v, %v", c.Languages, c.Tools)
+	}
+	c = resaved(func(c *Character) { c.Equipment.Shield = "shield" })
+	if err := keepProgress(&c, old, createRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if findItem(c.Inventory, "rope", "", false) < 0 || findItem(c.Inventory, "shield", "", false) < 0 {
+		t.Fatalf("re-save should keep the stored inventory and add the sent shield: %+v", c.Inventory)
+	}
+	if c.Purse.GP != 42 {
+		t.Fatalf("re-save should keep the purse: %+v", c.Purse)
+	}
+}
+
+func TestDualWieldedItemKeepsBothCopies(t *testing.T) {
+	c := &Character{Name: "D", Equipment: Equipment{Weapon: "dagger", OffHand: "dagger"}}
+	ensureEquipmentInInventory(c)
+	if len(c.Inventory) != 1 || c.Inventory[0].Quantity != 2 || carriedWeight(c.Inventory) != 2 {
+		t.Fatalf("two equipped daggers should be carried as 2: %+v", c.Inventory)
+	}
+	if _, err := unequipSlot(c, slotOff); err != nil {
+		t.Fatal(err)
+	}
+	if c.Inventory[0].Quantity != 2 || !c.Inventory[0].Equipped {
+		t.Fatalf("unequipping one hand should keep both daggers and the main-hand one equipped: %+v", c.Inventory)
+	}
+}
+
+func TestAddEquippedItemUsesItsSlot(t *testing.T) {
+	c := &Character{Name: "E"}
+	if _, err := carryItem(c, Item{Name: "Longsword", Quantity: 1, Weight: -1, Equipped: true}); err != nil {
+		t.Fatal(err)
+	}
+	ensureEquipmentInInventory(c)
+	if c.Equipment.Weapon != "longsword" || !c.Inventory[0].Equipped {
+		t.Fatalf("an equipped longsword should be in the main hand after a reconcile: %+v, %+v", c.Equipment, c.Inventory)
+	}
+	if _, err := carryItem(c, Item{Name: "Shield", Quantity: 1, Weight: -1, Equipped: true}); err != nil || c.Equipment.Shield != "shield" {
+		t.Fatalf("an equipped shield should go in the shield slot: %v, %+v", err, c.Equipment)
+	}
+	if _, err := carryItem(c, Item{Name: "Mace", Quantity: 1, Weight: -1, Equipped: true}); err == nil {
+		t.Fatal("expected an error equipping a mace in an occupied main hand")
+	}
+	if it, err := carryItem(c, Item{Name: "Rope", Quantity: 1, Weight: -1, Equipped: true}); err != nil || !it.Equipped {
+		t.Fatalf("other items keep the plain equipped flag: %+v, %v", it, err)
+	}
+}
+
+func TestGeneratedSpellsFollowTheClassTables(t *testing.T) {
+	for _, tc := range []struct {
+		class           string
+		level           int
+		known, prepared int
+	}{
+		{"sorcerer", 1, 2, 2},
+		{"warlock", 1, 2, 2},
+		{"bard", 1, 4, 4},
+		{"bard", 10, 14, 14},
+		{"ranger", 1, 0, 0},
+		{"wizard", 1, 6, -1},
+	} {
+		c, err := generateCharacter(GenerateOptions{Seed: 11, Class: tc.class, Level: tc.level})
+		if err != nil {
+			t.Fatal(err)
+		}
+		known, prepared := 0, 0
+		if c.Spellcasting != nil {
+			for _, s := range c.Spellcasting.Spells {
+				if s.Level > 0 {
+					known++
+					if s.Prepared {
+						prepared++
+					}
+				}
+			}
+		}
+		if tc.prepared < 0 {
+			tc.prepared = max(1, tc.level+abilityMod(c.AbilityScores.Intelligence))
+		}
+		if known != tc.known || prepared != tc.prepared {
+			t.Errorf("%s %d: %d spells, %d prepared; want %d, %d", tc.class, tc.level, known, prepared, tc.known, tc.prepared)
+		}
+	}
+}
+
+func TestLocalEquipmentLookup(t *testing.T) {
+	c := &Character{Name: "L", Equipment: Equipment{Weapon: "rapier", Armor: "half plate"}}
+	enrichEquipmentFrom(c, LocalEquipProv)
+	wi, ai := c.Equipment.WeaponInfo, c.Equipment.ArmorInfo
+	if wi.DamageDice != "1d8" || !wi.Finesse || wi.TwoHanded || wi.Category != "Martial" || wi.RangeNormal != 5 {
+		t.Fatalf("rapier data from the CSV = %+v", wi)
+	}
+	if ai.ArmorClass != 15 || !ai.DexBonus || ai.MaxDexBonus == nil || *ai.MaxDexBonus != 2 {
+		t.Fatalf("half plate data from the CSV = %+v", ai)
+	}
+	if am, ok := LocalEquipProv.FetchArmorMeta("plate"); !ok || am.ArmorClass != 18 || am.DexBonus {
+		t.Fatalf("plate data from the CSV = %+v, %v", am, ok)
+	}
+	if _, ok := LocalEquipProv.FetchWeaponMeta("rope"); ok {
+		t.Fatal("rope is not a weapon")
+	}
+}
+
+func TestAppearanceColorsAndTraitTablesFromCSV(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		p, err := rollAppearance(newRoller(seed), "dragonborn")
+		if err != nil || p.Hair != "none" {
+			t.Fatalf("dragonborn hair = %q, %v; want none from the race CSV", p.Hair, err)
+		}
+		p, _ = rollAppearance(newRoller(seed), "dark elf")
+		if r, _ := lookupRace("dark elf"); !containsString(r.Eyes, p.Eyes) || len(r.Hair) == 0 {
+			t.Fatalf("dark elf eyes %q not from %v", p.Eyes, r.Eyes)
+		}
+	}
+	if len(backgroundTraitTable("sage", "trait")) == 0 || len(csvBackgroundTraits["acolyte"]["trait"]) == 0 {
+		t.Fatal("expected the generic table from the non-SRD file and the acolyte table from the SRD file")
+	}
+}
+
+func TestKitRefusesArmorWithoutProficiency(t *testing.T) {
+	var ve *ValidationError
+	c := &Character{Name: "C", Class: "cleric", Background: "acolyte"}
+	err := applyStartingEquipment(c, []string{"a", "c"}, nil)
+	if !errors.As(err, &ve) || ve.Fields[0].Field != "equipment[1]" || !strings.Contains(ve.Fields[0].Message, "Chain Mail") {
+		t.Fatalf("expected chain mail to be refused for a cleric, got %v", err)
+	}
+	if err := applyStartingEquipment(c, []string{"a", "chain mail"}, nil); err == nil {
+		t.Fatal("expected chain mail picked by name to be refused too")
+	}
+	for seed := int64(1); seed <= 20; seed++ {
+		r := &Character{Name: "R", Class: "cleric", Background: "acolyte"}
+		if err := applyStartingEquipment(r, nil, newRoller(seed)); err != nil {
+			t.Fatal(err)
+		}
+		if findItem(r.Inventory, "chain mail", "", false) >= 0 {
+			t.Fatalf("seed %d: a random kit gave a cleric chain mail", seed)
+		}
+	}
+	if err := applyStartingEquipment(&Character{Name: "F", Class: "fighter"}, []string{"a"}, nil); err != nil {
+		t.Fatalf("a fighter can take chain mail: %v", err)
+	}
+}
+
+func TestSpellClassForMulticlassCaster(t *testing.T) {
+	c := &Character{Name: "T", Class: "fighter", Level: 6, Classes: []ClassLevel{{Class: "fighter", Level: 1}, {Class: "wizard", Level: 5}}}
+	cls, err := spellClassFor(c, "", canPrepareSpells)
+	if err != nil || cls != "wizard" {
+		t.Fatalf("default prepare class = %q, %v; want wizard", cls, err)
+	}
+	if lvl, ok := spellWithinSlotsOrError(c, cls, "fireball"); !ok || lvl != 3 {
+		t.Fatalf("wizard 5 should reach fireball, got %d, %v", lvl, ok)
+	}
+	var ve *ValidationError
+	if _, err := spellClassFor(c, "cleric", canPrepareSpells); !errors.As(err, &ve) || ve.Fields[0].Field != "class" {
+		t.Fatalf("expected a class error for a class the character lacks, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+// Layer: Infrastructure (data source adapter: load the feat catalog from CSV)
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const defaultFeatsFile = "5e-SRD-Feats.csv"
+
+var (
+	csvFeats       = map[string]Feat{}
+	featsCSVLoaded = false
+)
+
+/**
+*  tryLoadFeats attempts to load the feats CSV from the first path that works
+**/
+func tryLoadFeats(paths ...string) bool {
+	for _, p := range paths {
+		if err := loadFeatsFromCSV(p); err == nil {
+			featsCSVLoaded = true
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	if p := strings.TrimSpace(os.Getenv("FEATS_CSV")); p != "" && tryLoadFeats(p) {
+		return
+	}
+	if tryLoadFeats(
+		defaultFeatsFile,
+		filepath.Join("data", defaultFeatsFile),
+		filepath.Join("Data", defaultFeatsFile),
+		filepath.Join("DATA", defaultFeatsFile),
+	) {
+		return
+	}
+	if exe, err := os.Executable(); err == nil {
+		dir := filepath.Dir(exe)
+		_ = tryLoadFeats(
+			filepath.Join(dir, defaultFeatsFile),
+			filepath.Join(dir, "data", defaultFeatsFile),
+		)
+	}
+}
+
+/**
+*  loadFeatsFromCSV parses the feats CSV into the name-keyed catalog
+**/
+func loadFeatsFromCSV(path string) error {
+	rows, err := readCatalogCSV(path)
+	if err != nil {
+		return err
+	}
+	hdr := rows[0]
+	iName := findColumnIndex(hdr, "name")
+	if iName < 0 {
+		return errors.New("feats CSV missing required header: name")
+	}
+	iPrereq := findColumnIndex(hdr, "prerequisites")
+	iIncrease := findColumnIndex(hdr, "increase")
+	iDesc := findColumnIndex(hdr, "description")
+
+	tmp := map[string]Feat{}
+	for _, row := range rows[1:] {
+		name := cellAt(row, iName)
+		if name == "" {
+			continue
+		}
+		tmp[trimLower(name)] = Feat{
+			Name:          name,
+			Prerequisites: splitList(trimLower(cellAt(row, iPrereq))),
+			Increase:      splitList(trimLower(cellAt(row, iIncrease))),
+			Description:   cellAt(row, iDesc),
+		}
+	}
+	csvFeats = tmp
+	return nil
+}
+
+/**
+*  lookupFeat returns the catalog entry for a feat name
+**/
+func lookupFeat(name string) (Feat, bool) {
+	f, ok := csvFeats[trimLower(name)]
+	return f, ok
+}
+
+/**
+*  featNames returns the sorted lowercase feat names in the catalog
+**/
+func featNames() []string {
+	out := make([]string, 0, len(csvFeats))
+	for k := range csvFeats {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
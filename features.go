@@ -0,0 +1,158 @@
+// Layer: Domain (business rules: class feature numbers by level; no IO)
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/**
+*  scalingAt returns the value of the highest level threshold at or below level ("" when none applies)
+**/
+func scalingAt(values map[int]string, level int) string {
+	best, out := 0, ""
+	for l, v := range values {
+		if l <= level && l >= best {
+			best, out = l, v
+		}
+	}
+	return out
+}
+
+/**
+*  evalScalingValue fills in expressions like "level*5", "1+cha", "8+prof+wis" or "1d10+level";
+*  "level/2" rounds up (Arcane Recovery) and "level//2" rounds down (Wild Shape hours);
+*  anything else ("d6", "+2", "1/2") is returned unchanged
+**/
+func evalScalingValue(expr string, c *Character, classLevel int) string {
+	dice, total, computed := "", 0, false
+	for _, term := range strings.Split(expr, "+") {
+		term = strings.TrimSpace(term)
+		switch {
+		case term == "level":
+			total += classLevel
+		case strings.HasPrefix(term, "level*"):
+			n, err := strconv.Atoi(strings.TrimPrefix(term, "level*"))
+			if err != nil {
+				return expr
+			}
+			total += classLevel * n
+		case strings.HasPrefix(term, "level//"):
+			n, err := strconv.Atoi(strings.TrimPrefix(term, "level//"))
+			if err != nil || n <= 0 {
+				return expr
+			}
+			total += classLevel / n
+		case strings.HasPrefix(term, "level/"):
+			n, err := strconv.Atoi(strings.TrimPrefix(term, "level/"))
+			if err != nil || n <= 0 {
+				return expr
+			}
+			total += (classLevel + n - 1) / n
+		case term == "prof":
+			total += c.ProficiencyBonus
+		case abilityIndex(term) >= 0:
+			total += abilityMod(abilityArray(c.AbilityScores)[abilityIndex(term)])
+		case dice == "" && isDiceTerm(term):
+			dice = term
+			continue
+		default:
+			n, err := strconv.Atoi(term)
+			if err != nil {
+				return expr
+			}
+			total += n
+			continue
+		}
+		computed = true
+	}
+	if !computed {
+		return expr
+	}
+	if dice != "" {
+		return fmt.Sprintf("%s%+d", dice, total)
+	}
+	return strconv.Itoa(max(total, 1))
+}
+
+/**
+*  isDiceTerm reports whether s looks like "1d10"
+**/
+func isDiceTerm(s string) bool {
+	n, sides, ok := strings.Cut(s, "d")
+	_, err1 := strconv.Atoi(n)
+	_, err2 := strconv.Atoi(sides)
+	return ok && err1 == nil && err2 == nil
+}
+
+/**
+*  featureValues returns the scaling numbers of a feature at a class level
+**/
+func featureValues(c *Character, class, feature string, classLevel int) []FeatureValue {
+	var out []FeatureValue
+	for _, s := range featureScaling(class, feature) {
+		if v := scalingAt(s.Values, classLevel); v != "" {
+			out = append(out, FeatureValue{Label: s.Label, Value: evalScalingValue(v, c, classLevel)})
+		}
+	}
+	return out
+}
+
+/**
+*  withFeatureValues drops repeated feature names and fills in the numbers for the character's class levels
+**/
+func withFeatureValues(c *Character, feats []ClassFeature) []ClassFeature {
+	seen := map[string]bool{}
+	out := make([]ClassFeature, 0, len(feats))
+	for _, f := range feats {
+		key := f.Class + "/" + f.Name
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		f.Values = featureValues(c, f.Class, f.Name, classLevelOf(c, f.Class))
+		out = append(out, f)
+	}
+	return out
+}
+
+/**
+*  upgradedFeatures returns features the class already had whose numbers change between two class levels
+**/
+func upgradedFeatures(c *Character, class string, from, to int) []ClassFeature {
+	var out []ClassFeature
+	seen := map[string]bool{}
+	for _, f := range classFeaturesBetween(class, 0, from) {
+		if seen[f.Name] {
+			continue
+		}
+		seen[f.Name] = true
+		before := featureValues(c, class, f.Name, from)
+		f.Values = featureValues(c, class, f.Name, to)
+		if fmt.Sprint(before) != fmt.Sprint(f.Values) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+/**
+*  formatFeatureValues renders values as "uses 3, damage +2"
+**/
+func formatFeatureValues(values []FeatureValue) string {
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		parts = append(parts, v.Label+" "+v.Value)
+	}
+	return strings.Join(parts, ", ")
+}
+
+/**
+*  sortFeatures orders features by level, keeping catalog order within a level
+**/
+func sortFeatures(feats []ClassFeature) {
+	sort.SliceStable(feats, func(i, j int) bool { return feats[i].Level < feats[j].Level })
+}
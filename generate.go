@@ -0,0 +1,306 @@
+// Layer: Domain (business rules: random character generation; no IO)
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+/**
+*  pickRandom returns n distinct entries of list in random order (all of them when n is larger)
+**/
+func pickRandom(r *rand.Rand, list []string, n int) []string {
+	n = min(n, len(list))
+	out := make([]string, 0, n)
+	for _, i := range r.Perm(len(list))[:n] {
+		out = append(out, list[i])
+	}
+	return out
+}
+
+/**
+*  generatableRaces lists the catalog races a character can be; parents with subraces are left out
+**/
+func generatableRaces() []string {
+	parents := map[string]bool{}
+	for _, n := range raceNames() {
+		if r, ok := lookupRace(n); ok && r.Parent != "" {
+			parents[normalizeRaceName(r.Parent)] = true
+		}
+	}
+	var out []string
+	for _, n := range raceNames() {
+		if !parents[normalizeRaceName(n)] {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+/**
+*  randomSkillPicks draws the class's skill picks from the options the background doesn't already give
+**/
+func randomSkillPicks(r *rand.Rand, class, bg string) []string {
+	bgSkills := defaultBackgroundSkills(bg)
+	var options []string
+	for _, s := range classSkillOptions(class) {
+		if !containsString(bgSkills, s) {
+			options = append(options, s)
+		}
+	}
+	return pickRandom(r, options, classSkillCount(class))
+}
+
+/**
+*  randomSpellsForClass draws cantrips and count levelled spells (up to maxLevel) from the class list; cantrips and
+*  the first prepared levelled spells drawn are prepared
+**/
+func randomSpellsForClass(r *rand.Rand, class string, maxLevel, cantrips, count, prepared int) []Spell {
+	var zero, levelled []Spell
+	for _, s := range csvSpellsByClass[trimLower(class)] {
+		switch {
+		case s.Level == 0:
+			zero = append(zero, s)
+		case s.Level <= maxLevel:
+			levelled = append(levelled, s)
+		}
+	}
+	var out []Spell
+	for _, i := range r.Perm(len(zero))[:min(cantrips, len(zero))] {
+		s := zero[i]
+		s.Prepared = true
+		out = append(out, s)
+	}
+	for n, i := range r.Perm(len(levelled))[:min(count, len(levelled))] {
+		s := levelled[i]
+		s.Prepared = n < prepared
+		out = append(out, s)
+	}
+	slices.SortStableFunc(out, func(a, b Spell) int {
+		if a.Level != b.Level {
+			return a.Level - b.Level
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return out
+}
+
+/**
+*  generatedSpellCounts is how many levelled spells a generated caster starts with and how many of them are
+*  prepared: the class's spells-known (or spellbook) table when it has one, of which a spellbook caster prepares
+*  class level plus the casting modifier; otherwise class level (half for half-casters) plus the modifier, at least one
+**/
+func generatedSpellCounts(c *Character, class string) (int, int) {
+	ci, _ := lookupClass(class)
+	level := classLevelOf(c, class)
+	mod := abilityMod(abilityScoreByName(c, ci.SpellAbility))
+	if len(ci.SpellsKnown) > 0 {
+		known := levelTableValue(ci.SpellsKnown, level)
+		if !ci.Prepares {
+			return known, known
+		}
+		return known, min(known, max(1, level+mod))
+	}
+	if ci.Caster == "half" {
+		level /= 2
+	}
+	n := max(1, level+mod)
+	return n, n
+}
+
+/**
+*  proficientWithArmor reports whether a class armor list ("Light armor;Shields") covers the armor or shield
+**/
+func proficientWithArmor(ci ClassInfo, armor string) bool {
+	want := equipmentCategory(armor) + " armor"
+	if want == "shield armor" {
+		want = "shields"
+	}
+	for _, p := range ci.Armor {
+		if trimLower(p) == want {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+*  proficientWith reports whether a class proficiency list ("Simple weapons;Rapiers") covers the weapon
+**/
+func proficientWith(ci ClassInfo, weapon string) bool {
+	weapon = normalizeEquipment(weapon)
+	cat := equipmentCategory(weapon)
+	for _, p := range ci.Weapons {
+		p = trimLower(p)
+		switch {
+		case p == "simple weapons" && strings.HasPrefix(cat, "simple "),
+			p == "martial weapons" && strings.HasPrefix(cat, "martial "),
+			p == weapon+"s", p == weapon+"es":
+			return true
+		}
+	}
+	return false
+}
+
+/**
+*  spendASIsOnPriority spends every open ability score improvement on the highest priority abilities below 20
+**/
+func spendASIsOnPriority(c *Character, priority []string) error {
+	for _, cl := range openASILevels(c) {
+		var picks []string
+		for _, ab := range priority {
+			for abilityScoreByName(c, ab)+countString(picks, ab) < maxAbilityScore && len(picks) < 2 {
+				picks = append(picks, ab)
+			}
+		}
+		if len(picks) < 2 {
+			return nil
+		}
+		if _, err := applyImprovement(c, cl.Class, cl.Level, picks, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/**
+*  countString counts the entries of list equal to s
+**/
+func countString(list []string, s string) int {
+	n := 0
+	for _, x := range list {
+		if x == s {
+			n++
+		}
+	}
+	return n
+}
+
+/**
+*  titleWords capitalises every word ("hill dwarf cleric" -> "Hill Dwarf Cleric")
+**/
+func titleWords(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+/**
+*  generateCharacter builds a complete, legal random character; blank options are rolled from the catalogs
+*  and the same seed always gives the same character
+**/
+func generateCharacter(opts GenerateOptions) (Character, error) {
+	r := newRoller(opts.Seed)
+	level := opts.Level
+	if level == 0 {
+		level = 1
+	}
+	if level < 1 || level > maxCharacterLevel {
+		return Character{}, fieldError("level", fmt.Errorf("level must be between 1 and %d; %d given", maxCharacterLevel, level))
+	}
+	className := opts.Class
+	if strings.TrimSpace(className) == "" {
+		classes := classNames()
+		if len(classes) == 0 {
+			return Character{}, fieldError("class", fmt.Errorf("no classes loaded; run from the directory holding data/ or set CLASSES_CSV"))
+		}
+		className = classes[r.Intn(len(classes))]
+	}
+	ci, err := resolveClass(className)
+	if err != nil {
+		return Character{}, fieldError("class", err)
+	}
+	class := trimLower(ci.Name)
+	if _, err := normalizeGender(opts.Gender); err != nil {
+		return Character{}, fieldError("gender", err)
+	}
+	raceName := opts.Race
+	if strings.TrimSpace(raceName) == "" {
+		races := generatableRaces()
+		if len(races) > 0 {
+			raceName = races[r.Intn(len(races))]
+		}
+	}
+	race, err := resolveRace(raceName)
+	if err != nil {
+		return Character{}, fieldError("race", err)
+	}
+	bgName := opts.Background
+	if strings.TrimSpace(bgName) == "" && len(backgroundNames()) > 0 {
+		bgName = backgroundNames()[r.Intn(len(backgroundNames()))]
+	}
+	background, err := resolveBackground(bgName)
+	if err != nil {
+		return Character{}, fieldError("background", err)
+	}
+	bg := trimLower(background.Name)
+
+	priority := classPriority(class)
+	bonus, raceChoices, err := raceBonusesFor(race, nil, priority)
+	if err != nil {
+		return Character{}, fieldError("race", err)
+	}
+	base, roll, err := rollAbilityScores(r.Int63(), "class", priority)
+	if err != nil {
+		return Character{}, err
+	}
+	skills, err := finalSkills(class, bg, randomSkillPicks(r, class, bg))
+	if err != nil {
+		return Character{}, err
+	}
+
+	c := Character{
+		Name:              strings.TrimSpace(opts.Name),
+		Race:              normalizeRaceName(race.Name),
+		RaceChoices:       raceChoices,
+		Class:             class,
+		Level:             level,
+		Background:        bg,
+		BackgroundFeature: background.Feature,
+		AbilityScores:     addScores(base, bonus),
+		ProficiencyBonus:  profByLevel(level),
+		Skills:            skills,
+		AbilityRoll:       roll,
+	}
+	setBaseScores(&c)
+	if err := spendASIsOnPriority(&c, priority); err != nil {
+		return Character{}, err
+	}
+	if subs := subclassNames(class); len(subs) > 0 && level >= subclassLevel(class) {
+		if _, err := chooseSubclass(&c, class, subs[r.Intn(len(subs))]); err != nil {
+			return Character{}, err
+		}
+	}
+	c.Expertise = pickRandom(r, c.Skills, expertiseSlotsFor(&c))
+	if err := applyStartingEquipment(&c, nil, r); err != nil {
+		return Character{}, err
+	}
+	if err := assignProficiencies(&c, nil, nil, r); err != nil {
+		return Character{}, err
+	}
+	if ct := casterType(class); ct != "none" {
+		known, prepared := generatedSpellCounts(&c, class)
+		spells := randomSpellsForClass(r, class, maxSpellLevel(ct, level), cantripsKnown(class, level), known, prepared)
+		c.Spellcasting = &Spellcasting{Spells: spells}
+		refreshSpellcasting(&c)
+	}
+	if c.Name == "" {
+		if c.Name, err = randomName(r, c.Race, opts.Gender); err != nil {
+			c.Name = titleWords(c.Race + " " + c.Class)
+		}
+	}
+	if err := applyPersona(&c, Persona{}, true, r.Int63()); err != nil {
+		return Character{}, err
+	}
+	c.HPSeed = r.Int63()
+	if err := gainHitDice(&c, "avg", c.HPSeed); err != nil {
+		return Character{}, err
+	}
+	recalcHP(&c)
+	return c, nil
+}
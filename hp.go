@@ -0,0 +1,138 @@
+// Layer: Domain (business rules: hit points; no IO)
+
+package main
+
+import "fmt"
+
+/**
+*  averageHitDie returns the SRD fixed hit point gain for a hit die (d8 -> 5)
+**/
+func averageHitDie(die int) int {
+	return die/2 + 1
+}
+
+/**
+*  normalizeHPMethod maps a user supplied method to "average" or "roll"
+**/
+func normalizeHPMethod(m string) (string, error) {
+	switch trimLower(m) {
+	case "", "avg", "average", "fixed":
+		return "average", nil
+	case "roll", "rolled":
+		return "roll", nil
+	default:
+		return "", fmt.Errorf("unknown hp method %q (use avg or roll)", m)
+	}
+}
+
+/**
+*  hitDieFor returns the hit die of the character's first class, d8 when the class is unknown
+**/
+func hitDieFor(c *Character) int {
+	return classHitDie(c.Class)
+}
+
+/**
+*  classHitDie returns a class's hit die, d8 when the class is unknown
+**/
+func classHitDie(class string) int {
+	if ci, ok := lookupClass(class); ok && ci.HitDie > 0 {
+		return ci.HitDie
+	}
+	return 8
+}
+
+/**
+*  gainHitDice records hit die results for every level above 1 that has none yet
+**/
+func gainHitDice(c *Character, method string, seed int64) error {
+	return gainClassHitDice(c, c.Class, method, seed)
+}
+
+/**
+*  gainClassHitDice is gainHitDice for levels taken in class (multiclassing)
+**/
+func gainClassHitDice(c *Character, class, method string, seed int64) error {
+	m, err := normalizeHPMethod(method)
+	if err != nil {
+		return err
+	}
+	die := classHitDie(class)
+	for lvl := len(c.HitDieRolls) + 2; lvl <= c.Level; lvl++ {
+		gain := averageHitDie(die)
+		if m == "roll" {
+			gain = rollDie(newRoller(seed+int64(lvl)), die)
+		}
+		c.HitDieRolls = append(c.HitDieRolls, gain)
+	}
+	c.HPMethod, c.HPSeed = m, seed
+	return nil
+}
+
+/**
+*  computeMaxHP returns max HP: full hit die at level 1, recorded gains after, CON mod every level (min 1 per level)
+**/
+func computeMaxHP(c *Character) int {
+	if c.Level < 1 {
+		return 0
+	}
+	con := abilityMod(c.AbilityScores.Constitution)
+	total := max(1, hitDieFor(c)+con)
+	for _, gain := range c.HitDieRolls {
+		total += max(1, gain+con)
+	}
+	return total
+}
+
+/**
+*  recalcHP refreshes MaxHP after CON or level changes, keeping damage already taken
+**/
+func recalcHP(c *Character) {
+	old := c.MaxHP
+	c.MaxHP = computeMaxHP(c)
+	if old == 0 {
+		c.CurrentHP = c.MaxHP
+		return
+	}
+	c.CurrentHP = min(max(c.CurrentHP+c.MaxHP-old, 0), c.MaxHP)
+}
+
+/**
+*  ensureHP fills in hit points for characters saved before HP was tracked
+**/
+func ensureHP(c *Character) {
+	if c.MaxHP > 0 || c.Level < 1 {
+		return
+	}
+	_ = gainHitDice(c, "average", 0)
+	recalcHP(c)
+}
+
+/**
+*  applyDamage removes temporary hit points first, then current hit points
+**/
+func applyDamage(c *Character, dmg int) {
+	if dmg <= 0 {
+		return
+	}
+	fromTemp := min(c.TempHP, dmg)
+	c.TempHP -= fromTemp
+	c.CurrentHP = max(c.CurrentHP-(dmg-fromTemp), 0)
+}
+
+/**
+*  applyHealing restores hit points up to MaxHP
+**/
+func applyHealing(c *Character, amount int) {
+	if amount <= 0 {
+		return
+	}
+	c.CurrentHP = min(c.CurrentHP+amount, c.MaxHP)
+}
+
+/**
+*  setTempHP applies the SRD rule that temporary hit points don't stack; the higher value wins
+**/
+func setTempHP(c *Character, amount int) {
+	c.TempHP = max(c.TempHP, amount)
+}
@@ -0,0 +1,247 @@
+// Layer: Domain (business rules: ability score improvements and feats; no IO)
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const maxAbilityScore = 20
+
+/**
+*  raceBonusOf returns the racial ability deltas recorded for the character
+**/
+func raceBonusOf(c *Character) AbilityScores {
+	r, ok := lookupRace(c.Race)
+	if !ok {
+		return AbilityScores{}
+	}
+	if bonus, _, err := raceBonusesFor(r, c.RaceChoices, nil); err == nil {
+		return bonus
+	}
+	return r.Bonuses
+}
+
+/**
+*  setBaseScores derives the pre-race scores from the current scores and race
+**/
+func setBaseScores(c *Character) {
+	c.BaseAbilityScores = subScores(c.AbilityScores, raceBonusOf(c))
+}
+
+/**
+*  ensureBaseScores fills in base scores for characters saved before they were tracked
+**/
+func ensureBaseScores(c *Character) {
+	if c.BaseAbilityScores == (AbilityScores{}) && len(c.Improvements) == 0 {
+		setBaseScores(c)
+	}
+}
+
+/**
+*  rebuildAbilityScores recomputes scores as base + race + improvements (capped at 20) and refreshes HP
+**/
+func rebuildAbilityScores(c *Character) {
+	arr := abilityArray(addScores(c.BaseAbilityScores, raceBonusOf(c)))
+	for _, imp := range c.Improvements {
+		for i, inc := range abilityArray(imp.Increases) {
+			if inc > 0 {
+				arr[i] = max(arr[i], min(arr[i]+inc, maxAbilityScore))
+			}
+		}
+	}
+	c.AbilityScores = abilitiesFromArray(arr)
+	recalcHP(c)
+}
+
+/**
+*  asiLevels returns the class levels up to level that grant an Ability Score Improvement
+**/
+func asiLevels(class string, level int) []int {
+	var out []int
+	for _, f := range classFeaturesBetween(class, 0, level) {
+		if f.Name == asiFeatureName {
+			out = append(out, f.Level)
+		}
+	}
+	return out
+}
+
+/**
+*  openASILevels returns the class levels with an ASI the character has not spent yet
+**/
+func openASILevels(c *Character) []ClassLevel {
+	used := map[ClassLevel]bool{}
+	for _, imp := range c.Improvements {
+		used[ClassLevel{Class: improvementClass(c, imp), Level: imp.Level}] = true
+	}
+	var out []ClassLevel
+	for _, cl := range classLevels(c) {
+		for _, l := range asiLevels(cl.Class, cl.Level) {
+			if k := (ClassLevel{Class: cl.Class, Level: l}); !used[k] {
+				out = append(out, k)
+			}
+		}
+	}
+	return out
+}
+
+/**
+*  improvementClass returns the class an improvement was taken in; older records belong to the first class
+**/
+func improvementClass(c *Character, imp Improvement) string {
+	if imp.Class != "" {
+		return imp.Class
+	}
+	return trimLower(c.Class)
+}
+
+/**
+*  formatOpenASIs renders open ASIs as "fighter 4, fighter 6"
+**/
+func formatOpenASIs(open []ClassLevel) string {
+	parts := make([]string, 0, len(open))
+	for _, cl := range open {
+		parts = append(parts, fmt.Sprintf("%s %d", cl.Class, cl.Level))
+	}
+	return strings.Join(parts, ", ")
+}
+
+/**
+*  characterFeats returns the names of the feats taken, in the order they were taken
+**/
+func characterFeats(c *Character) []string {
+	var out []string
+	for _, imp := range c.Improvements {
+		if imp.Feat != "" {
+			out = append(out, imp.Feat)
+		}
+	}
+	return out
+}
+
+/**
+*  parseIncreases turns picks like "str", "str:2" or "dex+1" into ability deltas
+**/
+func parseIncreases(picks []string) (AbilityScores, error) {
+	var arr [6]int
+	for _, p := range picks {
+		name, amount := p, "1"
+		if i := strings.IndexAny(p, ":+"); i >= 0 {
+			name, amount = p[:i], p[i+1:]
+		}
+		idx := abilityIndex(name)
+		if idx < 0 {
+			return AbilityScores{}, fmt.Errorf("unknown ability %q (use str, dex, con, int, wis, cha)", strings.TrimSpace(name))
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(amount))
+		if err != nil || n < 1 {
+			return AbilityScores{}, fmt.Errorf("invalid increase %q", p)
+		}
+		arr[idx] += n
+	}
+	total := 0
+	for _, v := range arr {
+		total += v
+	}
+	if total != 2 {
+		return AbilityScores{}, fmt.Errorf("an ability score improvement is +2 to one ability or +1 to two; %d points given", total)
+	}
+	return abilitiesFromArray(arr), nil
+}
+
+/**
+*  checkFeatPrerequisites verifies prerequisites like "str:13" or "spellcasting"; "/" separates alternatives
+**/
+func checkFeatPrerequisites(c *Character, f Feat) error {
+	if req, bad := unmetRequirement(c, f.Prerequisites); bad {
+		return fmt.Errorf("%s requires %s", f.Name, req)
+	}
+	return nil
+}
+
+/**
+*  improvementField names the request field an improvement error belongs to
+**/
+func improvementField(feat string) string {
+	if strings.TrimSpace(feat) != "" {
+		return "feat"
+	}
+	return "asi"
+}
+
+/**
+*  applyImprovement spends an open ASI (the first one matching class and level; "" and 0 match any)
+*  on ability increases or a feat
+**/
+func applyImprovement(c *Character, class string, level int, increases []string, feat string) (Improvement, error) {
+	open := openASILevels(c)
+	if len(open) == 0 {
+		return Improvement{}, fmt.Errorf("%s has no unspent ability score improvement at level %d", c.Name, c.Level)
+	}
+	idx := slices.IndexFunc(open, func(cl ClassLevel) bool {
+		return (class == "" || cl.Class == trimLower(class)) && (level == 0 || cl.Level == level)
+	})
+	if idx < 0 {
+		want := trimLower(class)
+		if level > 0 {
+			want = strings.TrimSpace(want + " level " + strconv.Itoa(level))
+		}
+		return Improvement{}, fmt.Errorf("no unspent ability score improvement at %s (open: %s)", want, formatOpenASIs(open))
+	}
+
+	imp := Improvement{Class: open[idx].Class, Level: open[idx].Level}
+	feat = strings.TrimSpace(feat)
+	switch {
+	case feat != "":
+		f, ok := lookupFeat(feat)
+		if !ok {
+			return Improvement{}, unknownNameError("feat", feat, featNames())
+		}
+		if containsString(characterFeats(c), f.Name) {
+			return Improvement{}, fmt.Errorf("%s already has the %s feat", c.Name, f.Name)
+		}
+		if err := checkFeatPrerequisites(c, f); err != nil {
+			return Improvement{}, err
+		}
+		if len(f.Increase) > 0 {
+			pick := f.Increase[0]
+			if len(increases) == 1 {
+				pick = trimLower(increases[0])
+				if idx := abilityIndex(pick); idx >= 0 {
+					pick = abilityShortNames[idx]
+				}
+			}
+			if len(increases) > 1 || (len(increases) == 0 && len(f.Increase) > 1) || !containsString(f.Increase, pick) {
+				return Improvement{}, fmt.Errorf("%s gives +1 to one of %s", f.Name, strings.Join(f.Increase, ", "))
+			}
+			var arr [6]int
+			arr[abilityIndex(pick)] = 1
+			imp.Increases = abilitiesFromArray(arr)
+		} else if len(increases) > 0 {
+			return Improvement{}, fmt.Errorf("choose either ability increases or a feat, not both")
+		}
+		imp.Feat = f.Name
+	case len(increases) > 0:
+		inc, err := parseIncreases(increases)
+		if err != nil {
+			return Improvement{}, err
+		}
+		imp.Increases = inc
+	default:
+		return Improvement{}, fmt.Errorf("choose ability increases or a feat")
+	}
+
+	cur := abilityArray(c.AbilityScores)
+	for i, inc := range abilityArray(imp.Increases) {
+		if inc > 0 && cur[i]+inc > maxAbilityScore {
+			return Improvement{}, fmt.Errorf("%s %d %+d would exceed %d", strings.ToUpper(abilityShortNames[i]), cur[i], inc, maxAbilityScore)
+		}
+	}
+	c.Improvements = append(append([]Improvement(nil), c.Improvements...), imp)
+	rebuildAbilityScores(c)
+	return imp, nil
+}
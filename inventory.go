@@ -0,0 +1,245 @@
+// Layer: Domain (business rules: inventory, carried weight and encumbrance; no IO)
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	maxAttunedItems  = 3
+	carryMultiplier  = 15
+	encumberedStep   = 5
+	statusNormal     = "unencumbered"
+	statusEncumbered = "encumbered"
+	statusHeavy      = "heavily encumbered"
+	statusOver       = "over capacity"
+	statusImmobile   = "cannot move"
+)
+
+/**
+*  sameItem reports whether two item names refer to the same equipment entry
+**/
+func sameItem(a, b string) bool {
+	return normalizeEquipment(a) == normalizeEquipment(b)
+}
+
+/**
+*  findItem returns the index of the first stack with the name (and notes, when given), or -1
+**/
+func findItem(items []Item, name, notes string, matchNotes bool) int {
+	for i, it := range items {
+		if sameItem(it.Name, name) && (!matchNotes || strings.EqualFold(it.Notes, notes)) {
+			return i
+		}
+	}
+	return -1
+}
+
+/**
+*  attunedCount counts the items the character is attuned to
+**/
+func attunedCount(items []Item) int {
+	n := 0
+	for _, it := range items {
+		if it.Attuned {
+			n++
+		}
+	}
+	return n
+}
+
+/**
+*  addItem puts qty of an item into the inventory, stacking with an item of the same name and notes;
+*  weight < 0 uses the catalog weight (0 for items not in the CSV)
+**/
+func addItem(c *Character, item Item) (Item, error) {
+	var verr ValidationError
+	item.Name = strings.TrimSpace(item.Name)
+	item.Notes = strings.TrimSpace(item.Notes)
+	if item.Name == "" {
+		verr.add("item", "item name is required")
+	}
+	if item.Quantity <= 0 {
+		verr.add("quantity", "quantity must be at least 1 (got %d)", item.Quantity)
+	}
+	if item.Attuned && attunedCount(c.Inventory) >= maxAttunedItems && findItem(c.Inventory, item.Name, item.Notes, true) < 0 {
+		verr.add("attuned", "already attuned to %d items (max %d)", attunedCount(c.Inventory), maxAttunedItems)
+	}
+	if err := verr.errOrNil(); err != nil {
+		return Item{}, err
+	}
+	if item.Weight < 0 {
+		item.Weight, _ = equipmentWeight(item.Name)
+	}
+	item.Name = equipmentDisplayName(item.Name)
+	if i := findItem(c.Inventory, item.Name, item.Notes, true); i >= 0 {
+		c.Inventory[i].Quantity += item.Quantity
+		c.Inventory[i].Equipped = c.Inventory[i].Equipped || item.Equipped
+		c.Inventory[i].Attuned = c.Inventory[i].Attuned || item.Attuned
+		return c.Inventory[i], nil
+	}
+	c.Inventory = append(c.Inventory, item)
+	return item, nil
+}
+
+/**
+*  removeItem takes qty of an item out of the inventory (0 removes the whole stack); equipped items leave their slot
+**/
+func removeItem(c *Character, name string, qty int) (Item, error) {
+	if strings.TrimSpace(name) == "" {
+		return Item{}, fieldError("item", fmt.Errorf("item name is required"))
+	}
+	if qty < 0 {
+		return Item{}, fieldError("quantity", fmt.Errorf("quantity must not be negative (got %d)", qty))
+	}
+	i := findItem(c.Inventory, name, "", false)
+	if i < 0 {
+		return Item{}, fieldError("item", fmt.Errorf("%s does not carry %q", c.Name, name))
+	}
+	it := c.Inventory[i]
+	if qty > it.Quantity {
+		return Item{}, fieldError("quantity", fmt.Errorf("%s carries only %d %s", c.Name, it.Quantity, it.Name))
+	}
+	if qty == 0 || qty == it.Quantity {
+		c.Inventory = append(c.Inventory[:i:i], c.Inventory[i+1:]...)
+		clearEquippedSlots(c, it.Name)
+		it.Quantity = 0
+		return it, nil
+	}
+	c.Inventory[i].Quantity -= qty
+	return c.Inventory[i], nil
+}
+
+/**
+*  clearEquippedSlots empties every equipment slot holding the item
+**/
+func clearEquippedSlots(c *Character, name string) {
+	e := &c.Equipment
+	if e.Weapon != "" && sameItem(e.Weapon, name) {
+		e.Weapon = ""
+	}
+	if e.OffHand != "" && sameItem(e.OffHand, name) {
+		e.OffHand = ""
+	}
+	if e.Armor != "" && sameItem(e.Armor, name) {
+		e.Armor = ""
+	}
+	if e.Shield != "" && sameItem(e.Shield, name) {
+		e.Shield = ""
+	}
+}
+
+/**
+*  equippedSlotItems lists the items in the equipment slots
+**/
+func equippedSlotItems(e Equipment) []string {
+	var out []string
+	for _, s := range []string{e.Weapon, e.OffHand, e.Armor, e.Shield} {
+		if strings.TrimSpace(s) != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+/**
+*  ensureEquipmentInInventory adds slot items missing from the inventory, carries at least one of an item per
+*  slot holding it, and keeps the equipped flag of weapons and armor in step with the slots
+**/
+func ensureEquipmentInInventory(c *Character) {
+	slots := equippedSlotItems(c.Equipment)
+	for i := range c.Inventory {
+		t, _ := equipmentType(normalizeEquipment(c.Inventory[i].Name))
+		if t == "weapon" || t == "armor" {
+			c.Inventory[i].Equipped = false
+		}
+	}
+	for _, s := range slots {
+		held := 0
+		for _, o := range slots {
+			if sameItem(o, s) {
+				held++
+			}
+		}
+		if i := findItem(c.Inventory, s, "", false); i >= 0 {
+			c.Inventory[i].Equipped = true
+			c.Inventory[i].Quantity = max(c.Inventory[i].Quantity, held)
+			continue
+		}
+		_, _ = addItem(c, Item{Name: s, Quantity: held, Weight: -1, Equipped: true})
+	}
+}
+
+/**
+*  carriedWeight sums quantity x weight over the inventory
+**/
+func carriedWeight(items []Item) float64 {
+	total := 0.0
+	for _, it := range items {
+		total += float64(it.Quantity) * it.Weight
+	}
+	return math.Round(total*100) / 100
+}
+
+/**
+*  sizeCarryFactor doubles capacity for each size above Medium and halves it for Tiny
+**/
+func sizeCarryFactor(size string) float64 {
+	switch trimLower(size) {
+	case "tiny":
+		return 0.5
+	case "large":
+		return 2
+	case "huge":
+		return 4
+	case "gargantuan":
+		return 8
+	default:
+		return 1
+	}
+}
+
+/**
+*  encumbranceFor computes carried weight (items and coins), carrying capacity (STR x 15) and the resulting speed; with variant it applies
+*  the SRD variant rule (over STR x 5: -10 ft, over STR x 10: -20 ft)
+**/
+func encumbranceFor(c *Character, variant bool) Encumbrance {
+	str := float64(c.AbilityScores.Strength)
+	size, speed := "", 30
+	if r, ok := lookupRace(c.Race); ok {
+		size = r.Size
+		if r.Speed > 0 {
+			speed = r.Speed
+		}
+	}
+	f := sizeCarryFactor(size)
+	e := Encumbrance{
+		Carried:      carriedWeight(c.Inventory) + coinWeight(c.Purse),
+		Capacity:     str * carryMultiplier * f,
+		PushDragLift: str * carryMultiplier * 2 * f,
+		Variant:      variant,
+		Status:       statusNormal,
+		Speed:        speed,
+	}
+	switch {
+	case e.Carried > e.PushDragLift:
+		e.Status, e.Speed = statusImmobile, 0
+	case e.Carried > e.Capacity:
+		e.Status, e.Speed = statusOver, 5
+	case variant && e.Carried > str*encumberedStep*2*f:
+		e.Status, e.Speed = statusHeavy, max(speed-20, 0)
+	case variant && e.Carried > str*encumberedStep*f:
+		e.Status, e.Speed = statusEncumbered, max(speed-10, 0)
+	}
+	return e
+}
+
+/**
+*  formatWeight prints pounds without trailing zeros
+**/
+func formatWeight(lb float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", lb), "0"), ".") + " lb"
+}
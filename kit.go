@@ -0,0 +1,296 @@
+// Layer: Domain (business rules: class and background starting equipment; no IO)
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+/**
+*  parseKitItem splits a kit entry like "Javelin x4" into its name and quantity
+**/
+func parseKitItem(entry string) (string, int) {
+	entry = strings.TrimSpace(entry)
+	if i := strings.LastIndex(entry, " x"); i > 0 {
+		if n, err := strconv.Atoi(entry[i+2:]); err == nil && n > 0 {
+			return strings.TrimSpace(entry[:i]), n
+		}
+	}
+	return entry, 1
+}
+
+/**
+*  isWildcardItem reports whether a kit entry is a pick like "any martial melee weapon"
+**/
+func isWildcardItem(name string) bool {
+	return strings.HasPrefix(trimLower(name), anyGrant+" ")
+}
+
+/**
+*  hasAllWords reports whether every word of spec appears in category ("simple" in "simple melee")
+**/
+func hasAllWords(category, spec string) bool {
+	words := strings.Fields(category)
+	for _, w := range strings.Fields(spec) {
+		if !containsString(words, w) {
+			return false
+		}
+	}
+	return true
+}
+
+/**
+*  wildcardOptions lists the items a wildcard may become: a tool category ("any musical instrument"),
+*  an equipment category ("any arcane focus") or weapons ("any simple weapon", "any martial melee weapon")
+**/
+func wildcardOptions(wild string) []string {
+	spec := strings.TrimPrefix(trimLower(wild), anyGrant+" ")
+	var out []string
+	for _, t := range csvTools {
+		if t.Category == spec {
+			out = append(out, t.Name)
+		}
+	}
+	if len(out) > 0 {
+		return out
+	}
+	weapon := strings.HasSuffix(spec, " weapon")
+	spec = strings.TrimSuffix(spec, " weapon")
+	for _, n := range equipmentNames() {
+		cat := equipmentCategory(n)
+		t, _ := equipmentType(n)
+		if cat == spec || (weapon && t == "weapon" && hasAllWords(cat, spec)) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+/**
+*  wildcardAccepts reports whether an item name may fill the wildcard
+**/
+func wildcardAccepts(wild, name string) bool {
+	for _, o := range wildcardOptions(wild) {
+		if sameItem(o, name) {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+*  defaultWildcardPick fills a wildcard with an item the class is proficient with when there is one:
+*  the first option, or a random one with r
+**/
+func defaultWildcardPick(wild string, ci ClassInfo, r *rand.Rand) string {
+	options := wildcardOptions(wild)
+	var usable []string
+	for _, o := range options {
+		if t, _ := equipmentType(o); t != "weapon" || proficientWith(ci, o) {
+			usable = append(usable, o)
+		}
+	}
+	if len(usable) == 0 {
+		usable = options
+	}
+	switch {
+	case len(usable) == 0:
+		return ""
+	case r != nil:
+		return usable[r.Intn(len(usable))]
+	default:
+		return usable[0]
+	}
+}
+
+/**
+*  formatKitChoice prints a choice the way the SRD does: "(a) Greataxe or (b) any martial melee weapon"
+**/
+func formatKitChoice(ch EquipmentChoice) string {
+	parts := make([]string, 0, len(ch.Options))
+	for i, opt := range ch.Options {
+		parts = append(parts, fmt.Sprintf("(%c) %s", 'a'+i, strings.Join(opt, ", ")))
+	}
+	return strings.Join(parts, " or ")
+}
+
+/**
+*  unusableArmor returns the first armor or shield in a kit option the class is not proficient with, or ""
+**/
+func unusableArmor(opt []string, ci ClassInfo) string {
+	for _, entry := range opt {
+		name, _ := parseKitItem(entry)
+		if t, _ := equipmentType(normalizeEquipment(name)); t == "armor" && !proficientWithArmor(ci, name) {
+			return name
+		}
+	}
+	return ""
+}
+
+/**
+*  pickKitOption returns the option a pick selects and the item to put in its wildcard: an empty pick takes
+*  the first option whose armor the class can wear (a random one with r), a letter picks by position and an
+*  item name picks the option listing it; options with armor the class is not proficient with are refused
+**/
+func pickKitOption(ch EquipmentChoice, pick string, ci ClassInfo, r *rand.Rand) (int, string, error) {
+	pick = trimLower(pick)
+	idx, fill := -1, ""
+	switch {
+	case pick == "" || pick == "auto":
+		var usable []int
+		for i, opt := range ch.Options {
+			if unusableArmor(opt, ci) == "" {
+				usable = append(usable, i)
+			}
+		}
+		if len(usable) == 0 {
+			usable = []int{0}
+		}
+		if r != nil {
+			return usable[r.Intn(len(usable))], "", nil
+		}
+		return usable[0], "", nil
+	case len(pick) == 1 && pick[0] >= 'a' && int(pick[0]-'a') < len(ch.Options):
+		idx = int(pick[0] - 'a')
+	default:
+	options:
+		for i, opt := range ch.Options {
+			for _, entry := range opt {
+				name, _ := parseKitItem(entry)
+				if isWildcardItem(name) && wildcardAccepts(name, pick) {
+					idx, fill = i, pick
+					break options
+				}
+				if !isWildcardItem(name) && sameItem(name, pick) {
+					idx = i
+					break options
+				}
+			}
+		}
+	}
+	if idx < 0 {
+		return 0, "", fmt.Errorf("%q is not one of %s", pick, formatKitChoice(ch))
+	}
+	if armor := unusableArmor(ch.Options[idx], ci); armor != "" {
+		return 0, "", fmt.Errorf("%s is not proficient with %s; pick another option of %s", ci.Name, armor, formatKitChoice(ch))
+	}
+	return idx, fill, nil
+}
+
+/**
+*  kitItems turns kit entries into inventory items, putting fill into the first wildcard that accepts it
+**/
+func kitItems(entries []string, fill string, ci ClassInfo, r *rand.Rand) []Item {
+	var out []Item
+	for _, entry := range entries {
+		name, qty := parseKitItem(entry)
+		if isWildcardItem(name) {
+			if fill != "" && wildcardAccepts(name, fill) {
+				name, fill = fill, ""
+			} else {
+				name = defaultWildcardPick(name, ci, r)
+			}
+		}
+		if name != "" {
+			out = append(out, Item{Name: name, Quantity: qty, Weight: -1})
+		}
+	}
+	return out
+}
+
+/**
+*  startingKitItems resolves one pick per class equipment choice (missing picks are automatic) and adds the
+*  fixed class gear and the background's gear
+**/
+func startingKitItems(c *Character, picks []string, r *rand.Rand) ([]Item, error) {
+	ci, _ := lookupClass(c.Class)
+	kit, _ := lookupClassKit(c.Class)
+	if len(picks) > len(kit.Choices) {
+		return nil, fieldError("equipment", fmt.Errorf("%s has %d equipment choices, %d given", c.Class, len(kit.Choices), len(picks)))
+	}
+	var items []Item
+	var verr ValidationError
+	for i, ch := range kit.Choices {
+		pick := ""
+		if i < len(picks) {
+			pick = picks[i]
+		}
+		idx, fill, err := pickKitOption(ch, pick, ci, r)
+		if err != nil {
+			verr.add(fmt.Sprintf("equipment[%d]", i), "%v", err)
+			continue
+		}
+		items = append(items, kitItems(ch.Options[idx], fill, ci, r)...)
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+	items = append(items, kitItems(kit.Fixed, "", ci, r)...)
+	if bg, ok := lookupBackground(c.Background); ok {
+		items = append(items, kitItems(bg.Equipment, "", ci, r)...)
+	}
+	return items, nil
+}
+
+/**
+*  applyStartingEquipment gives a new character its class and background gear and the background's gold,
+*  then equips armor, a weapon and a shield into any empty slots
+**/
+func applyStartingEquipment(c *Character, picks []string, r *rand.Rand) error {
+	items, err := startingKitItems(c, picks, r)
+	if err != nil {
+		return err
+	}
+	for _, it := range items {
+		if _, err := addItem(c, it); err != nil {
+			return err
+		}
+	}
+	if bg, ok := lookupBackground(c.Background); ok {
+		c.Purse.GP += bg.Gold
+	}
+	equipStartingGear(c)
+	return nil
+}
+
+/**
+*  equipStartingGear fills empty slots from the inventory: the armor with the best base AC, the first
+*  melee weapon (else the first weapon) and a shield when the class may use one and the weapon leaves a hand free
+**/
+func equipStartingGear(c *Character) {
+	e := &c.Equipment
+	ci, _ := lookupClass(c.Class)
+	bestAC, armor, melee, ranged, shield := 0, "", "", "", ""
+	for _, it := range c.Inventory {
+		name := normalizeEquipment(it.Name)
+		switch cat := equipmentCategory(name); {
+		case cat == "light" || cat == "medium" || cat == "heavy":
+			if ac, _, ok := armorBaseAndDexCap(name); ok && ac > bestAC {
+				bestAC, armor = ac, name
+			}
+		case strings.HasSuffix(cat, " melee") && melee == "":
+			melee = name
+		case strings.HasSuffix(cat, " ranged") && ranged == "":
+			ranged = name
+		case cat == "shield":
+			shield = name
+		}
+	}
+	if e.Armor == "" {
+		e.Armor = armor
+	}
+	if e.Weapon == "" {
+		e.Weapon = melee
+		if melee == "" {
+			e.Weapon = ranged
+		}
+	}
+	canShield := strings.Contains(strings.ToLower(strings.Join(ci.Armor, ";")), "shields")
+	if e.Shield == "" && shield != "" && canShield && e.OffHand == "" && !isTwoHanded(*e, slotMain) {
+		e.Shield = shield
+	}
+	ensureEquipmentInInventory(c)
+}
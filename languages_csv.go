@@ -0,0 +1,108 @@
+// Layer: Infrastructure (data source adapter: load the language catalog from CSV)
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultLanguagesFile = "5e-SRD-Languages.csv"
+
+var (
+	csvLanguages       = []Language{}
+	languagesCSVLoaded = false
+)
+
+/**
+*  tryLoadLanguages attempts to load the languages CSV from the first path that works
+**/
+func tryLoadLanguages(paths ...string) bool {
+	for _, p := range paths {
+		if err := loadLanguagesFromCSV(p); err == nil {
+			languagesCSVLoaded = true
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	if p := strings.TrimSpace(os.Getenv("LANGUAGES_CSV")); p != "" && tryLoadLanguages(p) {
+		return
+	}
+	if tryLoadLanguages(
+		defaultLanguagesFile,
+		filepath.Join("data", defaultLanguagesFile),
+		filepath.Join("Data", defaultLanguagesFile),
+		filepath.Join("DATA", defaultLanguagesFile),
+	) {
+		return
+	}
+	if exe, err := os.Executable(); err == nil {
+		dir := filepath.Dir(exe)
+		_ = tryLoadLanguages(
+			filepath.Join(dir, defaultLanguagesFile),
+			filepath.Join(dir, "data", defaultLanguagesFile),
+		)
+	}
+}
+
+/**
+*  loadLanguagesFromCSV parses the languages CSV, keeping the file order (standard before exotic)
+**/
+func loadLanguagesFromCSV(path string) error {
+	rows, err := readCatalogCSV(path)
+	if err != nil {
+		return err
+	}
+	hdr := rows[0]
+	iName := findColumnIndex(hdr, "name")
+	iType := findColumnIndex(hdr, "type")
+	if iName < 0 || iType < 0 {
+		return errors.New("languages CSV missing required headers: name, type")
+	}
+	iScript := findColumnIndex(hdr, "script")
+	iSpeakers := findColumnIndex(hdr, "speakers")
+
+	var tmp []Language
+	for _, row := range rows[1:] {
+		name := cellAt(row, iName)
+		if name == "" {
+			continue
+		}
+		tmp = append(tmp, Language{
+			Name:     name,
+			Type:     trimLower(cellAt(row, iType)),
+			Script:   cellAt(row, iScript),
+			Speakers: splitList(cellAt(row, iSpeakers)),
+		})
+	}
+	csvLanguages = tmp
+	return nil
+}
+
+/**
+*  lookupLanguage returns the catalog entry for a language name
+**/
+func lookupLanguage(name string) (Language, bool) {
+	for _, l := range csvLanguages {
+		if trimLower(l.Name) == trimLower(name) {
+			return l, true
+		}
+	}
+	return Language{}, false
+}
+
+/**
+*  languageNames returns the lowercase language names in catalog order
+**/
+func languageNames() []string {
+	out := make([]string, 0, len(csvLanguages))
+	for _, l := range csvLanguages {
+		out = append(out, trimLower(l.Name))
+	}
+	return out
+}
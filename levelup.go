@@ -0,0 +1,223 @@
+// Layer: Domain (business rules: gaining levels; no IO)
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const maxCharacterLevel = 20
+
+const asiFeatureName = "Ability Score Improvement"
+
+/**
+*  levelUp raises a copy of c to level to and recomputes everything that depends on level.
+*  The new levels go to choices.Class (default: the first class), multiclassing if it is a new class;
+*  the other choices are picks made for the new levels and anything still open is listed in Choices.
+**/
+func levelUp(c Character, to int, hpMethod string, hpSeed int64, choices LevelUpChoices) (Character, LevelUpResult, error) {
+	from := c.Level
+	class := trimLower(c.Class)
+	if strings.TrimSpace(choices.Class) != "" {
+		ci, err := resolveClass(choices.Class)
+		if err != nil {
+			return c, LevelUpResult{}, fieldError("class", err)
+		}
+		if class == "" {
+			return c, LevelUpResult{}, fieldError("class", fmt.Errorf("%s has no class to multiclass from", c.Name))
+		}
+		class = trimLower(ci.Name)
+	}
+	if to == 0 {
+		to = from + 1
+	}
+	if to <= from {
+		return c, LevelUpResult{}, fieldError("to", fmt.Errorf("%s is already level %d", c.Name, from))
+	}
+	if to > maxCharacterLevel {
+		return c, LevelUpResult{}, fieldError("to", fmt.Errorf("level %d is above the maximum of %d", to, maxCharacterLevel))
+	}
+	if strings.TrimSpace(hpMethod) == "" {
+		hpMethod = c.HPMethod
+	}
+	if hpSeed == 0 {
+		hpSeed = c.HPSeed
+	}
+	if hpSeed == 0 {
+		hpSeed = newSeed()
+	}
+
+	oldClassLevel := classLevelOf(&c, class)
+	if class != "" && oldClassLevel == 0 {
+		if err := checkMulticlassPrereqs(&c, class); err != nil {
+			return c, LevelUpResult{}, fieldError("class", err)
+		}
+	}
+	if class != "" {
+		classes := classLevels(&c)
+		if oldClassLevel == 0 {
+			classes = append(classes, ClassLevel{Class: class})
+		}
+		for i := range classes {
+			if classes[i].Class == class {
+				classes[i].Level += to - from
+			}
+		}
+		if len(classes) > 1 {
+			c.Classes = classes
+		}
+	}
+
+	c.HitDieRolls = append([]int(nil), c.HitDieRolls...)
+	oldMax := c.MaxHP
+	c.Level = to
+	c.ProficiencyBonus = profByLevel(to)
+	if err := gainClassHitDice(&c, class, hpMethod, hpSeed); err != nil {
+		return c, LevelUpResult{}, fieldError("hp_method", err)
+	}
+	recalcHP(&c)
+	refreshSpellcasting(&c)
+	if strings.TrimSpace(choices.Subclass) != "" {
+		if _, err := chooseSubclass(&c, class, choices.Subclass); err != nil {
+			return c, LevelUpResult{}, fieldError("subclass", err)
+		}
+	}
+
+	newClassLevel := classLevelOf(&c, class)
+	res := LevelUpResult{
+		Class:            class,
+		ClassLevel:       newClassLevel,
+		From:             from,
+		To:               to,
+		HPGained:         c.MaxHP - oldMax,
+		ProficiencyBonus: c.ProficiencyBonus,
+		Features:         classFeaturesBetween(class, oldClassLevel, newClassLevel),
+	}
+	if sub := subclassOf(&c, class); sub != "" {
+		res.Features = append(res.Features, subclassFeaturesBetween(class, sub, oldClassLevel, newClassLevel)...)
+		sortFeatures(res.Features)
+	}
+	res.Features = withFeatureValues(&c, res.Features)
+	res.Upgraded = upgradedFeatures(&c, class, oldClassLevel, newClassLevel)
+	if len(choices.ASI) > 0 || strings.TrimSpace(choices.Feat) != "" {
+		imp, err := applyImprovement(&c, class, 0, choices.ASI, choices.Feat)
+		if err != nil {
+			return c, LevelUpResult{}, fieldError(improvementField(choices.Feat), err)
+		}
+		res.Improvement = &imp
+	}
+	learned, err := learnClassSpells(&c, class, choices.Spells)
+	if err != nil {
+		return c, LevelUpResult{}, fieldError("spells", err)
+	}
+	res.NewSpells = learned
+	if len(choices.Expertise) > 0 {
+		if c.Expertise, err = validateExpertise(&c, append(append([]string(nil), c.Expertise...), choices.Expertise...)); err != nil {
+			return c, LevelUpResult{}, fieldError("expertise", err)
+		}
+	}
+	res.Choices = pendingLevelChoices(&c, res)
+	return c, res, nil
+}
+
+/**
+*  refreshSpellcasting replaces the slot tables for the current levels; the spell list is copied, not shared
+**/
+func refreshSpellcasting(c *Character) {
+	if len(castingClasses(c)) == 0 && classLevelOf(c, "warlock") == 0 {
+		return
+	}
+	sc := Spellcasting{}
+	if c.Spellcasting != nil {
+		sc.Spells = append([]Spell(nil), c.Spellcasting.Spells...)
+	}
+	sc.SlotsByLevel, sc.PactSlots = characterSpellSlots(c)
+	c.Spellcasting = &sc
+	syncSubclassSpells(c)
+}
+
+/**
+*  learnClassSpells adds spells from the class list that the character can cast at its level in that class
+**/
+func learnClassSpells(c *Character, class string, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	ct := casterType(class)
+	if ct == "none" || c.Spellcasting == nil {
+		return nil, fmt.Errorf("%s can't cast spells", class)
+	}
+	pool := csvSpellsByClass[trimLower(class)]
+	level := classLevelOf(c, class)
+	maxL := maxSpellLevel(ct, level)
+	var out []string
+	for _, n := range names {
+		target := trimLower(n)
+		if target == "" || knowsSpell(c, target) {
+			continue
+		}
+		var found *Spell
+		for i := range pool {
+			if trimLower(pool[i].Name) == target {
+				found = &pool[i]
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("%q is not on the %s spell list", n, class)
+		}
+		if found.Level > maxL {
+			return nil, fmt.Errorf("%s is a level %d spell; %s level %d casts up to level %d", found.Name, found.Level, class, level, maxL)
+		}
+		c.Spellcasting.Spells = append(c.Spellcasting.Spells, Spell{Name: target, Level: found.Level})
+		out = append(out, target)
+	}
+	return out, nil
+}
+
+/**
+*  knowsSpell reports whether the spell is already in the character's list
+**/
+func knowsSpell(c *Character, name string) bool {
+	if c.Spellcasting == nil {
+		return false
+	}
+	for _, s := range c.Spellcasting.Spells {
+		if trimLower(s.Name) == trimLower(name) {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+*  pendingLevelChoices lists what the new levels grant that the player still has to pick
+**/
+func pendingLevelChoices(c *Character, res LevelUpResult) []string {
+	out := pendingSubclassChoices(c)
+	for _, cl := range openASILevels(c) {
+		out = append(out, fmt.Sprintf("%s level %d: ability score improvement (+2 to one ability, +1 to two, or a feat)", cl.Class, cl.Level))
+	}
+	if open := expertiseSlotsFor(c) - len(c.Expertise); open > 0 {
+		out = append(out, fmt.Sprintf("choose %d more expertise skills", open))
+	}
+	have := 0
+	if c.Spellcasting != nil {
+		for _, s := range c.Spellcasting.Spells {
+			if s.Level == 0 {
+				have++
+			}
+		}
+	}
+	if want := cantripsKnownFor(c); want > have {
+		out = append(out, fmt.Sprintf("choose %d more cantrips", want-have))
+	}
+	if ct := casterType(res.Class); ct != "none" {
+		before := res.ClassLevel - (res.To - res.From)
+		if newMax := maxSpellLevel(ct, res.ClassLevel); newMax > maxSpellLevel(ct, before) {
+			out = append(out, fmt.Sprintf("level %d %s spells are now available", newMax, res.Class))
+		}
+	}
+	return out
+}
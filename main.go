@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -21,17 +22,35 @@ const (
 func usage() {
 	app := os.Args[0]
 	fmt.Printf(`Usage:
-  %s create -name NAME [-race RACE] [-class CLASS] [-level N] [-str N -dex N -con N -int N -wis N -cha N] [-background BG | -bg BG] [-skills "skill1, skill2"]
+  %s create [-name NAME] [-gender male|female|neutral] [-race RACE [-race-bonus "dex,con"]] [-class CLASS] [-level N] [-method standard|pointbuy|roll [-seed N] [-assign order|class]] [-priority "int,con,dex,wis,cha,str"] [-str N -dex N -con N -int N -wis N -cha N] [-background BG | -bg BG] [-subclass SUBCLASS] [-skills "skill1, skill2"] [-expertise "skill1, skill2"] [-languages "lang1, lang2"] [-tools "tool1, tool2"] [-hp avg|roll [-hp-seed N]] [-equipment "a; battleaxe; auto" | -no-equipment | -gold avg|roll [-gold-seed N]]
+  %s generate [-seed N] [-count K] [-name NAME] [-gender male|female|neutral] [-class CLASS] [-race RACE] [-background BG] [-level N]
+  %s persona -name NAME [-roll [-seed N]] [-alignment AL] [-traits "trait one; trait two"] [-ideal TEXT] [-bond TEXT] [-flaw TEXT] [-age N] [-height 5'10"] [-weight LB] [-eyes COLOR] [-hair COLOR]
+  %s names [-race RACE] [-gender male|female|neutral] [-count N] [-seed N]
   %s view -name NAME_OR_SUBSTRING
   %s list
+  %s expertise -name NAME -skills "skill1, skill2"
+  %s proficiencies -name NAME [-languages "lang1, lang2"] [-tools "tool1, tool2"]
+  %s hp -name NAME [-damage N] [-heal N] [-temp N] [-recalc]
+  %s levelup -name NAME [-class CLASS] [-to N] [-hp avg|roll [-hp-seed N]] [-subclass SUBCLASS] [-asi "str,dex" | -feat FEAT] [-spells "spell1, spell2"] [-expertise "skill1, skill2"]
+  %s subclass -name NAME [-class CLASS] -subclass SUBCLASS
+  %s award (-name NAME | -chars "A, B, C") -xp N [-levelup [-hp avg|roll]]
+  %s improve -name NAME [-class CLASS] [-level N] (-asi "str:2" | -asi "str,dex" | -feat FEAT)
   %s delete -name NAME
-  %s equip -name NAME [-weapon WEAPON] [-armor ARMOR] [-shield SHIELD] [-slot SLOT]
-  %s prepare -name NAME -spell "SPELL NAME"
-  %s learn -name NAME -spell "SPELL NAME"
+  %s equip -name NAME [-weapon WEAPON [-slot main|off]] [-armor ARMOR] [-shield SHIELD] [-replace]
+  %s unequip -name NAME -slot main|off|armor|shield
+  %s swap-hands -name NAME
+  %s inventory add -name NAME -item ITEM [-qty N] [-weight LB] [-notes TEXT] [-equipped] [-attuned]
+  %s inventory remove -name NAME -item ITEM [-qty N]
+  %s inventory list -name NAME [-variant]
+  %s buy -name NAME -item ITEM [-qty N]
+  %s sell -name NAME -item ITEM [-qty N]
+  %s coins -name NAME [-add "10 gp 5 sp"] [-spend "3 gp"]
+  %s prepare -name NAME -spell "SPELL NAME" [-class CLASS]
+  %s learn -name NAME -spell "SPELL NAME" [-class CLASS]
   %s enrich [-limit N] [-dryrun] [-rps N] [-workers N] 
   %s inspect [-name NAME_OR_SUBSTRING]
   %s serve [-addr :8080]
-`, app, app, app, app, app, app, app, app, app, app)
+`, app, app, app, app, app, app, app, app, app, app, app, app, app, app, app, app, app, app, app, app, app, app, app, app, app, app, app, app)
 }
 
 
@@ -102,7 +121,7 @@ func maxSlotLevel(slots map[int]int) int {
 }
 
 
-func calcBaseScoresCLI(str, dex, con, intl, wis, cha int) (AbilityScores, bool) {
+func calcBaseScoresCLI(str, dex, con, intl, wis, cha int, priority []string) (AbilityScores, bool) {
 	raw := []int{str, dex, con, intl, wis, cha}
 	providedAll, providedAny := true, false
 	for _, v := range raw {
@@ -116,23 +135,77 @@ func calcBaseScoresCLI(str, dex, con, intl, wis, cha int) (AbilityScores, bool)
 	case providedAny:
 		return AbilityScores{def10(str), def10(dex), def10(con), def10(intl), def10(wis), def10(cha)}, true
 	default:
-		return assignStandardArray(), false
+		return assignStandardArray(priority), false
 	}
 }
 
 
-func applyRaceBonusesCLI(base AbilityScores, race string) AbilityScores {
-	rStr, rDex, rCon, rInt, rWis, rCha := raceBonusDeltas(race)
-	return AbilityScores{
-		Strength:     base.Strength + rStr,
-		Dexterity:    base.Dexterity + rDex,
-		Constitution: base.Constitution + rCon,
-		Intelligence: base.Intelligence + rInt,
-		Wisdom:       base.Wisdom + rWis,
-		Charisma:     base.Charisma + rCha,
+/**
+*  scoresForMethodCLI builds the final ability scores for create's -method flag
+**/
+func scoresForMethodCLI(method string, bonus AbilityScores, priority []string, seed int64, assign string, given AbilityScores) (AbilityScores, *AbilityRoll, error) {
+	m, err := normalizeAbilityMethod(method)
+	if err != nil {
+		return AbilityScores{}, nil, err
+	}
+	switch m {
+	case "pointbuy":
+		base, left, err := pointBuyScores(given)
+		if err != nil {
+			return AbilityScores{}, nil, err
+		}
+		fmt.Printf("Point buy: %d of %d points spent, %d left\n", pointBuyBudget-left, pointBuyBudget, left)
+		return applyRaceBonusesCLI(base, bonus), nil, nil
+	case "roll":
+		if seed == 0 {
+			seed = newSeed()
+		}
+		base, roll, err := rollAbilityScores(seed, assign, priority)
+		if err != nil {
+			return AbilityScores{}, nil, err
+		}
+		printAbilityRoll(roll)
+		return applyRaceBonusesCLI(base, bonus), roll, nil
+	case "standard":
+		return applyRaceBonusesCLI(assignStandardArray(priority), bonus), nil, nil
+	default:
+		a := abilityArray(given)
+		base, providedAny := calcBaseScoresCLI(a[0], a[1], a[2], a[3], a[4], a[5], priority)
+		if providedAny {
+			return base, nil, nil
+		}
+		return applyRaceBonusesCLI(base, bonus), nil, nil
+	}
+}
+
+/**
+*  applyStartingHP rolls or averages hit dice for levels above 1 and sets full HP
+**/
+func applyStartingHP(c *Character, method string, seed int64) error {
+	if seed == 0 {
+		seed = newSeed()
+	}
+	if err := gainHitDice(c, method, seed); err != nil {
+		return err
+	}
+	recalcHP(c)
+	return nil
+}
+
+func printAbilityRoll(r *AbilityRoll) {
+	if r == nil {
+		return
+	}
+	fmt.Printf("Rolled abilities (4d6 drop lowest, seed %d, assigned by %s):\n", r.Seed, r.Assign)
+	for i, dice := range r.Dice {
+		fmt.Printf("  %v -> %d\n", dice, r.Totals[i])
 	}
 }
 
+func applyRaceBonusesCLI(base AbilityScores, bonus AbilityScores) AbilityScores {
+	return addScores(base, bonus)
+}
+
 func parseSkillsCSV(s string) []string {
 	if strings.TrimSpace(s) == "" {
 		return nil
@@ -159,14 +232,14 @@ func buildSpellcastingCLI(class string, level int) *Spellcasting {
 
 func cmdCreate(args []string) {
 	fs := flag.NewFlagSet("create", flag.ExitOnError)
-	name := fs.String("name", "", "required")
+	name := fs.String("name", "", "name (default: generated for the race)")
+	gender := fs.String("gender", "", "male|female|neutral for a generated name")
 	race := fs.String("race", "", "")
+	raceBonus := fs.String("race-bonus", "", "comma separated abilities for flexible racial bonuses (half-elf)")
 	class := fs.String("class", "", "")
 	level := fs.Int("level", 1, "")
 	bgLong := fs.String("background", "", "optional")
 	bgShort := fs.String("bg", "", "optional")
-	_ = bgLong
-	_ = bgShort
 
 	str := fs.Int("str", 0, "")
 	dex := fs.Int("dex", 0, "")
@@ -174,36 +247,129 @@ func cmdCreate(args []string) {
 	intl := fs.Int("int", 0, "")
 	wis := fs.Int("wis", 0, "")
 	cha := fs.Int("cha", 0, "")
+	method := fs.String("method", "", "standard|pointbuy|roll")
+	hpMethod := fs.String("hp", "avg", "avg|roll hit points per level")
+	hpSeed := fs.Int64("hp-seed", 0, "seed for -hp roll (0 = random)")
+	seed := fs.Int64("seed", 0, "seed for -method roll (0 = random)")
+	assign := fs.String("assign", "", "order|class for -method roll")
+	priorityFlag := fs.String("priority", "", "comma separated ability order, e.g. \"int,con,dex,wis,cha,str\"")
 	skillsFlag := fs.String("skills", "", "comma separated")
+	expertiseFlag := fs.String("expertise", "", "comma separated skills to double proficiency in (rogue, bard)")
+	languagesFlag := fs.String("languages", "", "comma separated languages for the free picks of race and background")
+	toolsFlag := fs.String("tools", "", "comma separated tools for the free picks of race, class and background")
+	subclassFlag := fs.String("subclass", "", "subclass, once the class reaches its subclass level")
+	goldMethod := fs.String("gold", "", "avg|roll class starting gold (default: none)")
+	goldSeed := fs.Int64("gold-seed", 0, "seed for -gold roll (0 = random)")
+	equipmentFlag := fs.String("equipment", "", "starting equipment picks per class choice, \";\" separated: a letter, an item or auto")
+	noEquipment := fs.Bool("no-equipment", false, "start without class and background gear")
 	_ = fs.Parse(args)
 
-	if *name == "" {
-		fmt.Println("name is required")
-		os.Exit(2)
+	if strings.TrimSpace(*name) == "" {
+		generated, err := randomName(newRoller(newSeed()), *race, *gender)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		*name = uniqueCharacterName(generated)
+		fmt.Printf("No name given; generated %q\n", *name)
 	}
 
-	base, providedAny := calcBaseScoresCLI(*str, *dex, *con, *intl, *wis, *cha)
-	final := base
-	if !providedAny {
-		final = applyRaceBonusesCLI(base, *race)
+	classInfo, err := resolveClass(*class)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	priority, err := resolvePriority(parseSkillsCSV(*priorityFlag), *class)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	raceInfo, err := resolveRace(*race)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	bonus, raceChoices, err := raceBonusesFor(raceInfo, parseSkillsCSV(*raceBonus), priority)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	given := AbilityScores{*str, *dex, *con, *intl, *wis, *cha}
+	final, roll, err := scoresForMethodCLI(*method, bonus, priority, *seed, *assign, given)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
 	}
 
 
-	bg := "acolyte"
+	bgName := *bgLong
+	if strings.TrimSpace(bgName) == "" {
+		bgName = *bgShort
+	}
+	background, err := resolveBackground(bgName)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	bg := trimLower(background.Name)
 
-	provided := parseSkillsCSV(*skillsFlag)
+	skills, err := finalSkills(*class, bg, parseSkillsCSV(*skillsFlag))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
 	sc := buildSpellcastingCLI(*class, *level)
 
 	c := Character{
-		Name:             *name,
-		Race:             strings.ToLower(*race),
-		Class:            strings.ToLower(*class),
-		Level:            *level,
-		Background:       bg,
-		AbilityScores:    final,
-		ProficiencyBonus: profByLevel(*level),
-		Skills:           finalSkills(*class, bg, provided),
-		Spellcasting:     sc,
+		Name:              *name,
+		Race:              normalizeRaceName(raceInfo.Name),
+		RaceChoices:       raceChoices,
+		Class:             trimLower(classInfo.Name),
+		Level:             *level,
+		Background:        bg,
+		BackgroundFeature: background.Feature,
+		AbilityScores:     final,
+		ProficiencyBonus:  profByLevel(*level),
+		Skills:            skills,
+		Spellcasting:      sc,
+		AbilityRoll:       roll,
+	}
+	setBaseScores(&c)
+	if strings.TrimSpace(*subclassFlag) != "" {
+		if _, err := chooseSubclass(&c, "", *subclassFlag); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+	if c.Expertise, err = validateExpertise(&c, parseSkillsCSV(*expertiseFlag)); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if err := assignProficiencies(&c, parseSkillsCSV(*languagesFlag), parseSkillsCSV(*toolsFlag), nil); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if err := applyStartingHP(&c, *hpMethod, *hpSeed); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if *goldMethod != "" && *equipmentFlag != "" {
+		fmt.Println("-gold replaces the starting equipment; use -gold or -equipment, not both")
+		os.Exit(2)
+	}
+	if *goldMethod != "" {
+		if *goldSeed == 0 {
+			*goldSeed = newSeed()
+		}
+		if _, err := grantStartingGold(&c, *goldMethod, newRoller(*goldSeed)); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	} else if !*noEquipment {
+		if err := applyStartingEquipment(&c, splitTraits(*equipmentFlag), nil); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
 	}
 	upsertCharacter(c)
 	saveCharacters()
@@ -211,6 +377,185 @@ func cmdCreate(args []string) {
 }
 
 
+/**
+*  uniqueCharacterName appends " 2", " 3", ... until no stored character has the name
+**/
+func uniqueCharacterName(name string) string {
+	taken := func(n string) bool {
+		return slices.ContainsFunc(characters, func(c Character) bool { return strings.EqualFold(c.Name, n) })
+	}
+	out := name
+	for i := 2; taken(out); i++ {
+		out = fmt.Sprintf("%s %d", name, i)
+	}
+	return out
+}
+
+func cmdNames(args []string) {
+	fs := flag.NewFlagSet("names", flag.ExitOnError)
+	race := fs.String("race", "", "race or subrace (default: human)")
+	gender := fs.String("gender", "", "male|female|neutral")
+	count := fs.Int("count", 10, "")
+	seed := fs.Int64("seed", 0, "0 = random")
+	_ = fs.Parse(args)
+
+	if *seed == 0 {
+		*seed = newSeed()
+	}
+	names, err := randomNames(newRoller(*seed), *race, *gender, *count)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	for _, n := range names {
+		fmt.Println(n)
+	}
+}
+
+func cmdGenerate(args []string) {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	seed := fs.Int64("seed", 0, "seed for the first character; the next ones use seed+1, seed+2, ... (0 = random)")
+	count := fs.Int("count", 1, "number of characters to generate")
+	name := fs.String("name", "", "name (default: generated for the race)")
+	gender := fs.String("gender", "", "male|female|neutral for generated names")
+	class := fs.String("class", "", "class (default: random)")
+	race := fs.String("race", "", "race (default: random)")
+	bgLong := fs.String("background", "", "background (default: random)")
+	bgShort := fs.String("bg", "", "")
+	level := fs.Int("level", 1, "")
+	_ = fs.Parse(args)
+
+	if *count < 1 {
+		fmt.Println("count must be at least 1")
+		os.Exit(2)
+	}
+	if *seed == 0 {
+		*seed = newSeed()
+	}
+	bg := *bgLong
+	if strings.TrimSpace(bg) == "" {
+		bg = *bgShort
+	}
+	for i := 0; i < *count; i++ {
+		opts := GenerateOptions{Seed: *seed + int64(i), Name: *name, Class: *class, Race: *race, Background: bg, Gender: *gender, Level: *level}
+		c, err := generateCharacter(opts)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		c.Name = uniqueCharacterName(c.Name)
+		upsertCharacter(c)
+		fmt.Printf("generated %s: level %d %s %s, %s (seed %d)\n", c.Name, c.Level, c.Race, formatClassLevels(&c), c.Background, opts.Seed)
+	}
+	saveCharacters()
+}
+
+func printHitPoints(c *Character) {
+	fmt.Printf("Hit points: %d/%d", c.CurrentHP, c.MaxHP)
+	if c.TempHP > 0 {
+		fmt.Printf(" (+%d temp)", c.TempHP)
+	}
+	fmt.Printf("  [%s, d%d]\n", c.HPMethod, hitDieFor(c))
+}
+
+func printSkillTable(c *Character) {
+	fmt.Println("Skills:")
+	for _, sm := range computeSkillTable(c) {
+		mark := ""
+		switch {
+		case sm.Expertise:
+			mark = " (expertise)"
+		case sm.Proficient:
+			mark = " (proficient)"
+		}
+		fmt.Printf("  %s (%s): %+d%s\n", sm.Skill, strings.ToUpper(sm.Ability), sm.Modifier, mark)
+	}
+}
+
+func printSavingThrows(c *Character) {
+	fmt.Println("Saving throws:")
+	for _, st := range computeSavingThrows(c) {
+		mark := ""
+		if st.Proficient {
+			mark = " (proficient)"
+		}
+		fmt.Printf("  %s: %+d%s\n", strings.ToUpper(st.Ability), st.Modifier, mark)
+	}
+}
+
+func printClassBlock(c *Character) {
+	ci, ok := lookupClass(c.Class)
+	if !ok {
+		return
+	}
+	fmt.Printf("Hit die: d%d\n", ci.HitDie)
+	if len(ci.Armor) > 0 {
+		fmt.Printf("Armor proficiencies: %s\n", strings.Join(ci.Armor, ", "))
+	}
+	if len(ci.Weapons) > 0 {
+		fmt.Printf("Weapon proficiencies: %s\n", strings.Join(ci.Weapons, ", "))
+	}
+	if len(ci.Tools) > 0 {
+		fmt.Printf("Class tools: %s\n", strings.Join(ci.Tools, ", "))
+	}
+	printFeatures(c)
+}
+
+func printFeatures(c *Character) {
+	feats := characterFeatures(c)
+	if len(feats) == 0 {
+		return
+	}
+	fmt.Println("Class features:")
+	for _, f := range feats {
+		label := f.Name
+		if isMulticlass(c) {
+			label = f.Class + ": " + label
+		}
+		if f.Subclass != "" {
+			label += " [" + f.Subclass + "]"
+		}
+		if len(f.Values) > 0 {
+			fmt.Printf("  %s (%s)\n", label, formatFeatureValues(f.Values))
+		} else {
+			fmt.Printf("  %s\n", label)
+		}
+	}
+}
+
+func printRaceBlock(c *Character) {
+	r, ok := lookupRace(c.Race)
+	if !ok {
+		return
+	}
+	fmt.Printf("Size: %s  Speed: %d ft  Darkvision: %d ft\n", r.Size, r.Speed, r.Darkvision)
+	bonus, _, err := raceBonusesFor(r, c.RaceChoices, nil)
+	if err != nil {
+		bonus = r.Bonuses
+	}
+	fmt.Printf("Racial bonuses: %s\n", formatBonuses(bonus))
+	if len(r.Traits) > 0 {
+		fmt.Printf("Racial traits: %s\n", strings.Join(r.Traits, ", "))
+	}
+}
+
+func printBackgroundBlock(c *Character) {
+	bg, ok := lookupBackground(c.Background)
+	if !ok {
+		return
+	}
+	fmt.Printf("Background feature: %s\n", bg.Feature)
+	if bg.FeatureDesc != "" {
+		fmt.Printf("  %s\n", bg.FeatureDesc)
+	}
+	if len(bg.Tools) > 0 {
+		fmt.Printf("Background tools: %s\n", strings.Join(bg.Tools, ", "))
+	}
+	if len(bg.Equipment) > 0 {
+		fmt.Printf("Background equipment: %s (%d gp)\n", strings.Join(bg.Equipment, "; "), bg.Gold)
+	}
+}
+
 func printEquipmentBlock(c *Character) {
 	if strings.TrimSpace(c.Equipment.Weapon) != "" {
 		fmt.Printf("Main hand: %s\n", c.Equipment.Weapon)
@@ -263,17 +608,59 @@ func showFullCaster(c *Character, noSlots bool) {
 	}
 }
 
+func printPactSlots(c *Character) {
+	if c.Spellcasting == nil || len(c.Spellcasting.PactSlots) == 0 {
+		return
+	}
+	fmt.Println("Pact Magic slots:")
+	for lvl, n := range c.Spellcasting.PactSlots {
+		fmt.Printf("  Level %d: %d\n", lvl, n)
+	}
+}
+
+func showMulticlassCaster(c *Character, noSlots bool) {
+	if !noSlots {
+		fmt.Println(constSpellSlotsLine)
+		if ck := cantripsKnownFor(c); ck > 0 {
+			fmt.Printf("  Level 0: %d\n", ck)
+		}
+		printSpellSlotsBlock(c, slotKeys(c, 1), false)
+		printPactSlots(c)
+	}
+	for _, cl := range classLevels(c) {
+		sca := spellcastingAbilityForClass(cl.Class)
+		if sca == "" {
+			continue
+		}
+		abMod := abilityMod(abilityScoreByName(c, sca))
+		fmt.Printf("%s spellcasting: %s, save DC %d, attack %+d\n", cl.Class, sca, 8+c.ProficiencyBonus+abMod, c.ProficiencyBonus+abMod)
+	}
+}
+
 func printSpellcastingView(c *Character, noSlots bool) {
 	if c.Spellcasting == nil {
 		return
 	}
-	switch casterType(c.Class) {
-	case "half":
-		showHalfOrWarlockSlots(c, 1)
-	case "warlock":
-		showHalfOrWarlockSlots(c, 0)
-	case "full":
-		showFullCaster(c, noSlots)
+	if isMulticlass(c) {
+		showMulticlassCaster(c, noSlots)
+	} else {
+		switch casterType(c.Class) {
+		case "half":
+			showHalfOrWarlockSlots(c, 1)
+		case "warlock":
+			showHalfOrWarlockSlots(c, 0)
+		case "full":
+			showFullCaster(c, noSlots)
+		}
+	}
+	var always []string
+	for _, s := range c.Spellcasting.Spells {
+		if s.AlwaysPrepared {
+			always = append(always, s.Name)
+		}
+	}
+	if len(always) > 0 {
+		fmt.Printf("Always prepared: %s\n", strings.Join(always, ", "))
 	}
 }
 
@@ -291,37 +678,51 @@ func cmdView(args []string) {
 
 	fmt.Printf("Name: %s\n", c.Name)
 	fmt.Printf("Class: %s\n", strings.ToLower(c.Class))
+	if isMulticlass(c) {
+		fmt.Printf("Class levels: %s\n", formatClassLevels(c))
+	}
+	for _, cl := range classLevels(c) {
+		if sub := subclassOf(c, cl.Class); sub != "" {
+			fmt.Printf("Subclass: %s (%s)\n", sub, cl.Class)
+		}
+	}
+	printClassBlock(c)
 	fmt.Printf("Race: %s\n", strings.ToLower(c.Race))
+	printRaceBlock(c)
 	fmt.Printf("Background: %s\n", strings.ToLower(strings.TrimSpace(c.Background)))
+	printBackgroundBlock(c)
+	printPersona(c)
 	fmt.Printf("Level: %d\n", c.Level)
+	printXP(c)
 
 	printAbilityScores(c)
+	if r := c.AbilityRoll; r != nil {
+		fmt.Printf("Rolled totals (seed %d): %v\n", r.Seed, r.Totals)
+	}
+	printImprovements(c)
 	fmt.Printf("Proficiency bonus: %+d\n", c.ProficiencyBonus)
+	printHitPoints(c)
 
 	skillsOut := normalizeSkillList(c.Skills)
 	fmt.Printf("Skill proficiencies: %s\n", strings.Join(skillsOut, ", "))
+	if len(c.Expertise) > 0 {
+		fmt.Printf("Expertise: %s\n", strings.Join(c.Expertise, ", "))
+	}
+	printProficiencies(c)
+	printSkillTable(c)
 
 	printEquipmentBlock(c)
+	printPurse(c)
+	printEncumbrance(c, false)
 	printSpellcastingView(c, *noSlots)
 
 	fmt.Printf("Armor class: %d\n", computeArmorClass(c))
 	fmt.Printf("Initiative bonus: %d\n", computeInitiativeBonus(c))
 	fmt.Printf("Passive perception: %d\n", computePassivePerception(c))
+	printSavingThrows(c)
 }
 
 
-func normalizeSlotName(in string) string {
-	s := strings.ToLower(strings.TrimSpace(in))
-	switch s {
-	case "offhand":
-		return "off hand"
-	case "mainhand":
-		return "main hand"
-	default:
-		return s
-	}
-}
-
 func warnUnknownIfNeeded(x string) {
 	if x == "" {
 		return
@@ -331,63 +732,109 @@ func warnUnknownIfNeeded(x string) {
 	}
 }
 
-func equipWeapon(c *Character, weapon, slot string) bool {
-	if weapon == "" {
-		return false
+func cmdEquip(args []string) {
+	fs := flag.NewFlagSet("equip", flag.ExitOnError)
+	name := fs.String("name", "", "")
+	weapon := fs.String("weapon", "", "")
+	armor := fs.String("armor", "", "")
+	shield := fs.String("shield", "", "")
+	slot := fs.String("slot", "", "main or off (for -weapon)")
+	replace := fs.Bool("replace", false, "put displaced items back in the inventory")
+	_ = fs.Parse(args)
+
+	c := findCharLike(*name)
+	if c == nil {
+		fmt.Printf(constCharNotFoundFmt, *name)
+		return
 	}
-	w := normalizeEquipment(weapon)
-	warnUnknownIfNeeded(w)
-	if slot == "off hand" {
-		if c.Equipment.OffHand != "" {
-			fmt.Println("off hand already occupied")
-			return true
+
+	hand := slotMain
+	if *slot != "" {
+		s, err := parseSlot(*slot)
+		if err == nil && s != slotMain && s != slotOff {
+			err = fmt.Errorf("a weapon goes in the main or off hand, not %s", s)
+		}
+		if err != nil {
+			fmt.Println(fieldError("slot", err))
+			os.Exit(2)
+		}
+		hand = s
+	}
+
+	updated := *c
+	updated.Inventory = append([]Item(nil), c.Inventory...)
+	var done []string
+	for _, eq := range []struct{ kind, slot, item string }{
+		{"weapon", hand, *weapon},
+		{"armor", slotArmor, *armor},
+		{"shield", slotShield, *shield},
+	} {
+		if eq.item == "" {
+			continue
+		}
+		warnUnknownIfNeeded(normalizeEquipment(eq.item))
+		displaced, err := equipSlot(&updated, eq.slot, eq.item, *replace)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		if eq.kind == "weapon" {
+			done = append(done, fmt.Sprintf("Equipped weapon %s to %s", normalizeEquipment(eq.item), eq.slot))
+		} else {
+			done = append(done, fmt.Sprintf("Equipped %s %s", eq.kind, normalizeEquipment(eq.item)))
+		}
+		for _, d := range displaced {
+			done = append(done, fmt.Sprintf("  %s went back to the inventory", d))
 		}
-		c.Equipment.OffHand = w
-		fmt.Printf("Equipped weapon %s to off hand\n", w)
-		return true
-	}
-	if c.Equipment.Weapon != "" {
-		fmt.Println("main hand already occupied")
-		return true
 	}
-	c.Equipment.Weapon = w
-	hand := "main hand"
-	if slot != "" && slot != "main hand" {
-		hand = slot
+	if len(done) > 0 {
+		for _, line := range done {
+			fmt.Println(line)
+		}
+		printHandWarnings(updated.Equipment)
+		enrichEquipmentFrom(&updated, LocalEquipProv)
+		*c = updated
+		saveCharacters()
 	}
-	fmt.Printf("Equipped weapon %s to %s\n", w, hand)
-	return true
 }
 
-func equipArmor(c *Character, armor string) bool {
-	if armor == "" {
-		return false
+func printHandWarnings(e Equipment) {
+	for _, w := range handWarnings(e) {
+		fmt.Printf("(warning) %s\n", w)
 	}
-	a := normalizeEquipment(armor)
-	warnUnknownIfNeeded(a)
-	c.Equipment.Armor = a
-	fmt.Printf("Equipped armor %s\n", a)
-	return true
 }
 
-func equipShield(c *Character, shield string) bool {
-	if shield == "" {
-		return false
+func cmdUnequip(args []string) {
+	fs := flag.NewFlagSet("unequip", flag.ExitOnError)
+	name := fs.String("name", "", "")
+	slot := fs.String("slot", "", "main, off, armor or shield")
+	_ = fs.Parse(args)
+
+	c := findCharLike(*name)
+	if c == nil {
+		fmt.Printf(constCharNotFoundFmt, *name)
+		return
 	}
-	sh := normalizeEquipment(shield)
-	warnUnknownIfNeeded(sh)
-	c.Equipment.Shield = sh
-	fmt.Printf("Equipped shield %s\n", sh)
-	return true
+	s, err := parseSlot(*slot)
+	if err != nil {
+		fmt.Println(fieldError("slot", err))
+		os.Exit(2)
+	}
+	updated := *c
+	updated.Inventory = append([]Item(nil), c.Inventory...)
+	old, err := unequipSlot(&updated, s)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	*c = updated
+	saveCharacters()
+	fmt.Printf("Unequipped %s from the %s slot (kept in inventory)\n", old, s)
 }
 
-func cmdEquip(args []string) {
-	fs := flag.NewFlagSet("equip", flag.ExitOnError)
+func cmdSwapHands(args []string) {
+	fs := flag.NewFlagSet("swap-hands", flag.ExitOnError)
 	name := fs.String("name", "", "")
-	weapon := fs.String("weapon", "", "")
-	armor := fs.String("armor", "", "")
-	shield := fs.String("shield", "", "")
-	slot := fs.String("slot", "", "")
 	_ = fs.Parse(args)
 
 	c := findCharLike(*name)
@@ -395,21 +842,169 @@ func cmdEquip(args []string) {
 		fmt.Printf(constCharNotFoundFmt, *name)
 		return
 	}
+	updated := *c
+	if err := swapHands(&updated); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	enrichEquipmentFrom(&updated, LocalEquipProv)
+	*c = updated
+	saveCharacters()
+	fmt.Println("Swapped hands")
+	printEquipmentBlock(c)
+	printHandWarnings(c.Equipment)
+}
 
-	s := normalizeSlotName(*slot)
-	changed := false
-	if equipWeapon(c, *weapon, s) {
-		changed = true
+func printItem(it Item) {
+	line := fmt.Sprintf("  %dx %s (%s)", it.Quantity, it.Name, formatWeight(float64(it.Quantity)*it.Weight))
+	var flags []string
+	if it.Equipped {
+		flags = append(flags, "equipped")
+	}
+	if it.Attuned {
+		flags = append(flags, "attuned")
 	}
-	if equipArmor(c, *armor) {
-		changed = true
+	if len(flags) > 0 {
+		line += " [" + strings.Join(flags, ", ") + "]"
+	}
+	if it.Notes != "" {
+		line += " - " + it.Notes
+	}
+	fmt.Println(line)
+}
+
+func printEncumbrance(c *Character, variant bool) {
+	e := encumbranceFor(c, variant)
+	fmt.Printf("Carrying: %s of %s (push/drag/lift %s), %s, speed %d ft\n",
+		formatWeight(e.Carried), formatWeight(e.Capacity), formatWeight(e.PushDragLift), e.Status, e.Speed)
+}
+
+func printInventory(c *Character, variant bool) {
+	fmt.Println("Inventory:")
+	if len(c.Inventory) == 0 {
+		fmt.Println("  (empty)")
 	}
-	if equipShield(c, *shield) {
-		changed = true
+	for _, it := range c.Inventory {
+		printItem(it)
 	}
-	if changed {
+	printPurse(c)
+	printEncumbrance(c, variant)
+}
+
+func printPurse(c *Character) {
+	fmt.Printf("Purse: %s (worth %s)\n", formatPurse(c.Purse), formatCost(purseValue(c.Purse)))
+}
+
+func cmdTrade(cmd string, args []string) {
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	name := fs.String("name", "", "required")
+	item := fs.String("item", "", "SRD equipment name")
+	qty := fs.Int("qty", 1, "")
+	_ = fs.Parse(args)
+
+	c := findCharLike(*name)
+	if c == nil {
+		fmt.Printf(constCharNotFoundFmt, *name)
+		return
+	}
+	trade, verb := buyItem, "Bought"
+	if cmd == "sell" {
+		trade, verb = sellItem, "Sold"
+	}
+	cp, err := trade(c, *item, *qty)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	saveCharacters()
+	fmt.Printf("%s %d %s for %s\n", verb, *qty, equipmentDisplayName(*item), formatCost(cp))
+	printPurse(c)
+}
+
+func cmdCoins(args []string) {
+	fs := flag.NewFlagSet("coins", flag.ExitOnError)
+	name := fs.String("name", "", "required")
+	add := fs.String("add", "", "coins to put in, e.g. \"10 gp 5 sp\"")
+	spend := fs.String("spend", "", "amount to pay, change is made automatically")
+	_ = fs.Parse(args)
+
+	c := findCharLike(*name)
+	if c == nil {
+		fmt.Printf(constCharNotFoundFmt, *name)
+		return
+	}
+	purse := c.Purse
+	if *add != "" {
+		in, err := parseCoins(*add)
+		if err != nil {
+			fmt.Println(fieldError("add", err))
+			os.Exit(2)
+		}
+		purse = addCoins(purse, in)
+	}
+	if *spend != "" {
+		cp, err := parseCost(*spend)
+		if err == nil {
+			purse, err = spendCoins(purse, cp)
+		}
+		if err != nil {
+			fmt.Println(fieldError("spend", err))
+			os.Exit(2)
+		}
+	}
+	if purse != c.Purse {
+		c.Purse = purse
+		saveCharacters()
+	}
+	printPurse(c)
+}
+
+func cmdInventory(args []string) {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = strings.ToLower(args[0]), args[1:]
+	}
+	fs := flag.NewFlagSet("inventory "+action, flag.ExitOnError)
+	name := fs.String("name", "", "required")
+	item := fs.String("item", "", "item name (SRD equipment or anything else)")
+	qty := fs.Int("qty", 1, "quantity to add, or to remove (0 removes the whole stack)")
+	weight := fs.Float64("weight", -1, "weight per item in lb (default: from the equipment CSV)")
+	notes := fs.String("notes", "", "")
+	equipped := fs.Bool("equipped", false, "")
+	attuned := fs.Bool("attuned", false, "")
+	variant := fs.Bool("variant", false, "use the variant encumbrance rule")
+	_ = fs.Parse(args)
+
+	c := findCharLike(*name)
+	if c == nil {
+		fmt.Printf(constCharNotFoundFmt, *name)
+		return
+	}
+	switch action {
+	case "add":
+		it, err := carryItem(c, Item{Name: *item, Quantity: *qty, Weight: *weight, Notes: *notes, Equipped: *equipped, Attuned: *attuned})
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
 		saveCharacters()
+		fmt.Printf("Added %d %s (now %d)\n", *qty, it.Name, it.Quantity)
+	case "remove", "rm":
+		it, err := removeItem(c, *item, *qty)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		saveCharacters()
+		fmt.Printf("Removed %s (%d left)\n", it.Name, it.Quantity)
+	case "list", "ls":
+		printInventory(c, *variant)
+		return
+	default:
+		fmt.Printf("unknown inventory action %q (use add, remove or list)\n", action)
+		os.Exit(2)
 	}
+	printEncumbrance(c, *variant)
 }
 
 
@@ -428,23 +1023,26 @@ func validatePrepareInputs(name, spell string) bool {
 	return true
 }
 
-func ensureCanPrepare(c *Character) bool {
+func ensureCanPrepare(c *Character, class string) bool {
 	if c == nil {
 		return false
 	}
-	if casterType(c.Class) == "none" {
+	if casterType(class) == "none" {
 		fmt.Println("this class can't cast spells")
 		return false
 	}
-	if learnsSpells(c.Class) && !preparesSpells(c.Class) {
+	if learnsSpells(class) && !preparesSpells(class) {
 		fmt.Println("this class learns spells and can't prepare them")
 		return false
 	}
 	return true
 }
 
-func spellWithinSlotsOrError(c *Character, target string) (int, bool) {
-	slots := spellSlotsFor(casterType(c.Class), c.Level)
+/**
+*  spellWithinSlotsOrError returns the spell's level when the class's own levels give slots of that level
+**/
+func spellWithinSlotsOrError(c *Character, class, target string) (int, bool) {
+	slots := spellSlotsFor(casterType(class), classLevelOf(c, class))
 	if spellLvl, ok := spellLevelByName(target); ok {
 		if max := maxSlotLevel(slots); max == 0 || spellLvl > max {
 			fmt.Println(constSpellTooHighMsg)
@@ -477,6 +1075,7 @@ func cmdPrepare(args []string) {
 	fs := flag.NewFlagSet("prepare", flag.ExitOnError)
 	name := fs.String("name", "", "required")
 	spell := fs.String("spell", "", "required")
+	class := fs.String("class", "", "class whose list the spell is prepared from (default: the first class that prepares)")
 	_ = fs.Parse(args)
 
 	merged := mergeSpellArgs(*spell, fs.Args())
@@ -485,12 +1084,20 @@ func cmdPrepare(args []string) {
 	}
 
 	c := findCharLike(*name)
-	if !ensureCanPrepare(c) {
+	if c == nil {
+		return
+	}
+	cls, err := spellClassFor(c, *class, canPrepareSpells)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if !ensureCanPrepare(c, cls) {
 		return
 	}
 
 	target := strings.ToLower(strings.TrimSpace(merged))
-	lvl, ok := spellWithinSlotsOrError(c, target)
+	lvl, ok := spellWithinSlotsOrError(c, cls, target)
 	if !ok {
 		return
 	}
@@ -498,9 +1105,418 @@ func cmdPrepare(args []string) {
 }
 
 
+/**
+*  printPersona prints alignment, appearance and the personality entries that are set
+**/
+func printPersona(c *Character) {
+	p := c.Persona
+	if p.Alignment != "" {
+		fmt.Printf("Alignment: %s\n", p.Alignment)
+	}
+	var looks []string
+	if p.Age > 0 {
+		looks = append(looks, fmt.Sprintf("Age: %d", p.Age))
+	}
+	if p.Height > 0 {
+		looks = append(looks, "Height: "+formatHeight(p.Height))
+	}
+	if p.Weight > 0 {
+		looks = append(looks, fmt.Sprintf("Weight: %d lb", p.Weight))
+	}
+	if p.Eyes != "" {
+		looks = append(looks, "Eyes: "+p.Eyes)
+	}
+	if p.Hair != "" {
+		looks = append(looks, "Hair: "+p.Hair)
+	}
+	if len(looks) > 0 {
+		fmt.Println(strings.Join(looks, "  "))
+	}
+	if len(p.PersonalityTraits) > 0 {
+		fmt.Println("Personality traits:")
+		for _, t := range p.PersonalityTraits {
+			fmt.Printf("  - %s\n", t)
+		}
+	}
+	for _, line := range [][2]string{{"Ideal", p.Ideal}, {"Bond", p.Bond}, {"Flaw", p.Flaw}} {
+		if line[1] != "" {
+			fmt.Printf("%s: %s\n", line[0], line[1])
+		}
+	}
+}
+
+/**
+*  splitTraits splits "trait one; trait two" (traits themselves may contain commas)
+**/
+func splitTraits(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ";") {
+		if t := strings.TrimSpace(p); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func cmdPersona(args []string) {
+	fs := flag.NewFlagSet("persona", flag.ExitOnError)
+	name := fs.String("name", "", "required")
+	roll := fs.Bool("roll", false, "roll everything not given from the background and race tables")
+	seed := fs.Int64("seed", 0, "seed for -roll (0 = random)")
+	alignment := fs.String("alignment", "", "e.g. \"lawful good\" or LG")
+	traits := fs.String("traits", "", "personality traits separated by ;")
+	ideal := fs.String("ideal", "", "")
+	bond := fs.String("bond", "", "")
+	flaw := fs.String("flaw", "", "")
+	age := fs.Int("age", 0, "")
+	height := fs.String("height", "", "5'10\" or inches")
+	weight := fs.Int("weight", 0, "pounds")
+	eyes := fs.String("eyes", "", "")
+	hair := fs.String("hair", "", "")
+	_ = fs.Parse(args)
+
+	c := findCharLike(*name)
+	if c == nil {
+		fmt.Printf(constCharNotFoundFmt, *name)
+		return
+	}
+	given := Persona{
+		Alignment:         *alignment,
+		PersonalityTraits: splitTraits(*traits),
+		Ideal:             *ideal,
+		Bond:              *bond,
+		Flaw:              *flaw,
+		Age:               *age,
+		Weight:            *weight,
+		Eyes:              *eyes,
+		Hair:              *hair,
+	}
+	if strings.TrimSpace(*height) != "" {
+		h, err := parseHeight(*height)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		given.Height = h
+	}
+	if *seed == 0 {
+		*seed = newSeed()
+	}
+	if err := applyPersona(c, given, *roll, *seed); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	saveCharacters()
+	printPersona(c)
+}
+
+func printProficiencies(c *Character) {
+	if len(c.Languages) > 0 {
+		fmt.Printf("Languages: %s\n", strings.Join(c.Languages, ", "))
+	}
+	if len(c.Tools) > 0 {
+		fmt.Printf("Tool proficiencies: %s\n", strings.Join(c.Tools, ", "))
+	}
+}
+
+func cmdProficiencies(args []string) {
+	fs := flag.NewFlagSet("proficiencies", flag.ExitOnError)
+	name := fs.String("name", "", "required")
+	languages := fs.String("languages", "", "comma separated, replaces the free language picks")
+	tools := fs.String("tools", "", "comma separated, replaces the free tool picks")
+	_ = fs.Parse(args)
+
+	c := findCharLike(*name)
+	if c == nil {
+		fmt.Printf(constCharNotFoundFmt, *name)
+		return
+	}
+	langPicks, toolPicks := parseSkillsCSV(*languages), parseSkillsCSV(*tools)
+	if langPicks == nil {
+		langPicks = freePicks(kindLanguage, languageGrants(c), c.Languages)
+	}
+	if toolPicks == nil {
+		toolPicks = freePicks(kindTool, toolGrants(c), c.Tools)
+	}
+	if err := assignProficiencies(c, langPicks, toolPicks, nil); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	saveCharacters()
+	printProficiencies(c)
+}
+
+func cmdExpertise(args []string) {
+	fs := flag.NewFlagSet("expertise", flag.ExitOnError)
+	name := fs.String("name", "", "required")
+	skills := fs.String("skills", "", "comma separated, replaces the current expertise list")
+	_ = fs.Parse(args)
+
+	c := findCharLike(*name)
+	if c == nil {
+		fmt.Printf(constCharNotFoundFmt, *name)
+		return
+	}
+	picks, err := validateExpertise(c, parseSkillsCSV(*skills))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	c.Expertise = picks
+	saveCharacters()
+	fmt.Printf("Expertise: %s\n", strings.Join(picks, ", "))
+}
+
+func cmdHP(args []string) {
+	fs := flag.NewFlagSet("hp", flag.ExitOnError)
+	name := fs.String("name", "", "required")
+	damage := fs.Int("damage", 0, "damage taken (temp HP absorbs first)")
+	heal := fs.Int("heal", 0, "hit points restored")
+	temp := fs.Int("temp", 0, "temporary hit points gained")
+	recalc := fs.Bool("recalc", false, "recompute max HP from class, level and CON")
+	_ = fs.Parse(args)
+
+	c := findCharLike(*name)
+	if c == nil {
+		fmt.Printf(constCharNotFoundFmt, *name)
+		return
+	}
+	if *recalc {
+		recalcHP(c)
+	}
+	applyDamage(c, *damage)
+	applyHealing(c, *heal)
+	setTempHP(c, *temp)
+	saveCharacters()
+	printHitPoints(c)
+}
+
+func cmdLevelUp(args []string) {
+	fs := flag.NewFlagSet("levelup", flag.ExitOnError)
+	name := fs.String("name", "", "required")
+	class := fs.String("class", "", "class that gains the levels; a new class multiclasses (default: the first class)")
+	to := fs.Int("to", 0, "target character level (default: one level up)")
+	hpMethod := fs.String("hp", "", "avg or roll (default: the method used so far)")
+	hpSeed := fs.Int64("hp-seed", 0, "seed for -hp roll (default: the character's seed)")
+	spells := fs.String("spells", "", "comma separated spells to learn with the new levels")
+	expertise := fs.String("expertise", "", "comma separated skills to add as expertise")
+	asi := fs.String("asi", "", `ability increases for an open ASI, e.g. "str:2" or "str,dex"`)
+	feat := fs.String("feat", "", "feat to take instead of ability increases")
+	subclass := fs.String("subclass", "", "subclass to choose when the class reaches its subclass level")
+	_ = fs.Parse(args)
+
+	c := findCharLike(*name)
+	if c == nil {
+		fmt.Printf(constCharNotFoundFmt, *name)
+		return
+	}
+	choices := LevelUpChoices{
+		Subclass:  *subclass,
+		Class:     *class,
+		Spells:    parseSkillsCSV(*spells),
+		Expertise: parseSkillsCSV(*expertise),
+		ASI:       parseSkillsCSV(*asi),
+		Feat:      *feat,
+	}
+	updated, res, err := levelUp(*c, *to, *hpMethod, *hpSeed, choices)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	*c = updated
+	saveCharacters()
+	printLevelUp(c, res)
+}
+
+func printLevelUp(c *Character, res LevelUpResult) {
+	fmt.Printf("%s is now level %d (was %d)\n", c.Name, res.To, res.From)
+	if isMulticlass(c) {
+		fmt.Printf("Class levels: %s\n", formatClassLevels(c))
+	}
+	fmt.Printf("Proficiency bonus: %+d\n", res.ProficiencyBonus)
+	fmt.Printf("Hit point gain: +%d\n", res.HPGained)
+	printHitPoints(c)
+	if len(res.Features) > 0 {
+		fmt.Println("New class features:")
+		for _, f := range res.Features {
+			fmt.Printf("  %s %d: %s", f.Class, f.Level, f.Name)
+			if len(f.Values) > 0 {
+				fmt.Printf(" (%s)", formatFeatureValues(f.Values))
+			}
+			fmt.Println()
+		}
+	}
+	if len(res.Upgraded) > 0 {
+		fmt.Println("Improved class features:")
+		for _, f := range res.Upgraded {
+			fmt.Printf("  %s: %s\n", f.Name, formatFeatureValues(f.Values))
+		}
+	}
+	if c.Spellcasting != nil {
+		printSpellSlotsBlock(c, slotKeys(c, 1), true)
+		printPactSlots(c)
+	}
+	if res.Improvement != nil {
+		fmt.Printf("Improvement: %s\n", formatImprovement(*res.Improvement))
+		printAbilityScores(c)
+	}
+	if len(res.NewSpells) > 0 {
+		fmt.Printf("Learned spells: %s\n", strings.Join(res.NewSpells, ", "))
+	}
+	if len(res.Choices) > 0 {
+		fmt.Println("Choices to make:")
+		for _, ch := range res.Choices {
+			fmt.Printf("  - %s\n", ch)
+		}
+	}
+}
+
+func printXP(c *Character) {
+	if ready := levelForXP(c.XP); ready > c.Level {
+		fmt.Printf("XP: %d (can level up to %d)\n", c.XP, ready)
+	} else if next := xpForNextLevel(c.Level); next > 0 {
+		fmt.Printf("XP: %d (next level at %d)\n", c.XP, next)
+	} else {
+		fmt.Printf("XP: %d\n", c.XP)
+	}
+}
+
+func cmdAward(args []string) {
+	fs := flag.NewFlagSet("award", flag.ExitOnError)
+	name := fs.String("name", "", "character to award")
+	chars := fs.String("chars", "", "comma separated characters to split the xp between")
+	xp := fs.Int("xp", 0, "experience points (split evenly with -chars)")
+	auto := fs.Bool("levelup", false, "level up automatically when a threshold is reached")
+	hpMethod := fs.String("hp", "", "avg or roll for automatic level ups (default: the method used so far)")
+	_ = fs.Parse(args)
+
+	names := parseSkillsCSV(*chars)
+	if strings.TrimSpace(*name) != "" {
+		names = append(names, *name)
+	}
+	if len(names) == 0 || *xp == 0 {
+		usage()
+		return
+	}
+	targets := make([]*Character, 0, len(names))
+	copies := make([]Character, 0, len(names))
+	for _, n := range names {
+		c := findCharLike(n)
+		if c == nil {
+			fmt.Printf(constCharNotFoundFmt, n)
+			return
+		}
+		targets = append(targets, c)
+		copies = append(copies, *c)
+	}
+	updated, awards, err := awardXPSplit(copies, *xp, *auto, *hpMethod)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	for i := range targets {
+		*targets[i] = updated[i]
+	}
+	saveCharacters()
+	for i, a := range awards {
+		fmt.Printf("%s: +%d XP (total %d)\n", a.Name, a.Gained, a.XP)
+		switch {
+		case a.LevelUp != nil:
+			printLevelUp(targets[i], *a.LevelUp)
+		case a.ReadyLevel > a.Level:
+			fmt.Printf("  can level up to %d (levelup -name %q -to %d)\n", a.ReadyLevel, a.Name, a.ReadyLevel)
+		}
+	}
+}
+
+func cmdSubclass(args []string) {
+	fs := flag.NewFlagSet("subclass", flag.ExitOnError)
+	name := fs.String("name", "", "required")
+	class := fs.String("class", "", "class the subclass belongs to (default: the first class)")
+	subclass := fs.String("subclass", "", "required")
+	_ = fs.Parse(args)
+
+	c := findCharLike(*name)
+	if c == nil {
+		fmt.Printf(constCharNotFoundFmt, *name)
+		return
+	}
+	sc, err := chooseSubclass(c, *class, *subclass)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	saveCharacters()
+	fmt.Printf("Subclass: %s (%s)\n", sc.Name, sc.Class)
+	for _, f := range subclassFeaturesBetween(sc.Class, sc.Name, 0, classLevelOf(c, sc.Class)) {
+		fmt.Printf("  Level %d: %s\n", f.Level, f.Name)
+	}
+}
+
+func cmdImprove(args []string) {
+	fs := flag.NewFlagSet("improve", flag.ExitOnError)
+	name := fs.String("name", "", "required")
+	class := fs.String("class", "", "class whose ASI to spend (default: any)")
+	level := fs.Int("level", 0, "class level of the ASI to spend (default: the first open one)")
+	asi := fs.String("asi", "", `ability increases, e.g. "str:2" or "str,dex"`)
+	feat := fs.String("feat", "", "feat to take instead of ability increases")
+	_ = fs.Parse(args)
+
+	c := findCharLike(*name)
+	if c == nil {
+		fmt.Printf(constCharNotFoundFmt, *name)
+		return
+	}
+	imp, err := applyImprovement(c, *class, *level, parseSkillsCSV(*asi), *feat)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	saveCharacters()
+	fmt.Printf("%s level %d: %s\n", imp.Class, imp.Level, formatImprovement(imp))
+	printAbilityScores(c)
+}
+
+func formatImprovement(imp Improvement) string {
+	inc := formatBonuses(imp.Increases)
+	switch {
+	case imp.Feat != "" && inc != "":
+		return "feat " + imp.Feat + " (" + inc + ")"
+	case imp.Feat != "":
+		return "feat " + imp.Feat
+	default:
+		return inc
+	}
+}
+
+func printImprovements(c *Character) {
+	fmt.Printf("Base scores: %s\n", formatScores(c.BaseAbilityScores))
+	if bonus := formatBonuses(raceBonusOf(c)); bonus != "" {
+		fmt.Printf("Racial increases: %s\n", bonus)
+	}
+	for _, imp := range c.Improvements {
+		fmt.Printf("Improvement at %s level %d: %s\n", improvementClass(c, imp), imp.Level, formatImprovement(imp))
+	}
+	if open := openASILevels(c); len(open) > 0 {
+		fmt.Printf("Unspent ability score improvements: %s\n", formatOpenASIs(open))
+	}
+}
+
+func formatScores(s AbilityScores) string {
+	parts := make([]string, 0, len(abilityShortNames))
+	for i, v := range abilityArray(s) {
+		parts = append(parts, fmt.Sprintf("%s %d", strings.ToUpper(abilityShortNames[i]), v))
+	}
+	return strings.Join(parts, ", ")
+}
+
 func cmdList() {
 	for _, c := range characters {
-		fmt.Printf("- %s (%s, level %d, %s)\n", c.Name, c.Class, c.Level, c.Race)
+		class := c.Class
+		if isMulticlass(&c) {
+			class = formatClassLevels(&c)
+		}
+		fmt.Printf("- %s (%s, level %d, %s)\n", c.Name, class, c.Level, c.Race)
 		fmt.Printf("Background: %s  ProficiencyBonus: %d\n", c.Background, c.ProficiencyBonus)
 	}
 }
@@ -524,6 +1540,7 @@ func cmdLearn(args []string) {
 	fs := flag.NewFlagSet("learn", flag.ExitOnError)
 	name := fs.String("name", "", "required")
 	spell := fs.String("spell", "", "required")
+	class := fs.String("class", "", "class whose list the spell is learned from (default: the first class that learns)")
 	_ = fs.Parse(args)
 
 	if *spell != "" && len(fs.Args()) > 0 {
@@ -539,24 +1556,22 @@ func cmdLearn(args []string) {
 		fmt.Printf(constCharNotFoundFmt, *name)
 		return
 	}
-	if casterType(ch.Class) == "none" {
+	cls, err := spellClassFor(ch, *class, canLearnSpells)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if casterType(cls) == "none" {
 		fmt.Println("this class can't cast spells")
 		return
 	}
-	if preparesSpells(ch.Class) {
+	if preparesSpells(cls) {
 		fmt.Println("this class prepares spells and can't learn them")
 		return
 	}
 
 	target := strings.ToLower(strings.TrimSpace(*spell))
-	slots := spellSlotsFor(casterType(ch.Class), ch.Level)
-	if spellLvl, ok := spellLevelByName(target); ok {
-		if max := maxSlotLevel(slots); max == 0 || spellLvl > max {
-			fmt.Println(constSpellTooHighMsg)
-			return
-		}
-	} else {
-		fmt.Println(constSpellTooHighMsg)
+	if _, ok := spellWithinSlotsOrError(ch, cls, target); !ok {
 		return
 	}
 
@@ -673,14 +1688,44 @@ func main() {
 		serveCommand(os.Args[2:])
 	case "create":
 		cmdCreate(os.Args[2:])
+	case "generate", "gen":
+		cmdGenerate(os.Args[2:])
+	case "names":
+		cmdNames(os.Args[2:])
+	case "persona":
+		cmdPersona(os.Args[2:])
 	case "view":
 		cmdView(os.Args[2:])
 	case "list":
 		cmdList()
+	case "hp":
+		cmdHP(os.Args[2:])
+	case "expertise":
+		cmdExpertise(os.Args[2:])
+	case "proficiencies", "languages", "tools":
+		cmdProficiencies(os.Args[2:])
+	case "levelup", "level-up":
+		cmdLevelUp(os.Args[2:])
+	case "subclass":
+		cmdSubclass(os.Args[2:])
+	case "award":
+		cmdAward(os.Args[2:])
+	case "improve", "asi":
+		cmdImprove(os.Args[2:])
 	case "delete":
 		cmdDelete(os.Args[2:])
 	case "equip":
 		cmdEquip(os.Args[2:])
+	case "unequip":
+		cmdUnequip(os.Args[2:])
+	case "swap-hands", "swap":
+		cmdSwapHands(os.Args[2:])
+	case "inventory", "inv":
+		cmdInventory(os.Args[2:])
+	case "buy", "sell":
+		cmdTrade(os.Args[1], os.Args[2:])
+	case "coins", "purse":
+		cmdCoins(os.Args[2:])
 	case "prepare", "prepare-spell":
 		cmdPrepare(os.Args[2:])
 	case "learn", "learn-spell":
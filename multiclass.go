@@ -0,0 +1,194 @@
+// Layer: Domain (business rules: multiclassing and combined spell slots; no IO)
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/**
+*  classLevels returns the character's class breakdown; single-class characters get one entry
+**/
+func classLevels(c *Character) []ClassLevel {
+	if len(c.Classes) > 0 {
+		return append([]ClassLevel(nil), c.Classes...)
+	}
+	if strings.TrimSpace(c.Class) == "" {
+		return nil
+	}
+	return []ClassLevel{{Class: trimLower(c.Class), Level: c.Level}}
+}
+
+/**
+*  isMulticlass reports whether the character has levels in more than one class
+**/
+func isMulticlass(c *Character) bool {
+	return len(classLevels(c)) > 1
+}
+
+/**
+*  classLevelOf returns the character's level in one class (0 when it has none)
+**/
+func classLevelOf(c *Character, class string) int {
+	for _, cl := range classLevels(c) {
+		if cl.Class == trimLower(class) {
+			return cl.Level
+		}
+	}
+	return 0
+}
+
+/**
+*  spellClassFor picks the class a spell is prepared or learned through: the named class, which the character
+*  must have levels in, else the first of its classes that can use spells that way
+**/
+func spellClassFor(c *Character, class string, can func(class string) bool) (string, error) {
+	if class = trimLower(class); class != "" {
+		if classLevelOf(c, class) == 0 {
+			return "", fieldError("class", fmt.Errorf("%s has no levels in %s (has %s)", c.Name, class, formatClassLevels(c)))
+		}
+		return class, nil
+	}
+	for _, cl := range classLevels(c) {
+		if can(cl.Class) {
+			return cl.Class, nil
+		}
+	}
+	return trimLower(c.Class), nil
+}
+
+/**
+*  formatClassLevels renders the breakdown as "fighter 2 / wizard 3"
+**/
+func formatClassLevels(c *Character) string {
+	parts := make([]string, 0, 2)
+	for _, cl := range classLevels(c) {
+		parts = append(parts, fmt.Sprintf("%s %d", cl.Class, cl.Level))
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("level %d", c.Level)
+	}
+	return strings.Join(parts, " / ")
+}
+
+/**
+*  unmetRequirement returns the first requirement like "str:13", "str:13/dex:13" or "spellcasting" that c fails
+**/
+func unmetRequirement(c *Character, reqs []string) (string, bool) {
+	scores := abilityArray(c.AbilityScores)
+	for _, req := range reqs {
+		met := false
+		for _, alt := range strings.Split(req, "/") {
+			alt = strings.TrimSpace(alt)
+			if alt == "spellcasting" {
+				met = met || len(castingClasses(c)) > 0 || casterType(c.Class) == "warlock"
+				continue
+			}
+			ab, min, _ := strings.Cut(alt, ":")
+			n, err := strconv.Atoi(min)
+			if idx := abilityIndex(ab); idx >= 0 && err == nil && scores[idx] >= n {
+				met = true
+			}
+		}
+		if !met {
+			return strings.ReplaceAll(strings.ReplaceAll(req, ":", " "), "/", " or "), true
+		}
+	}
+	return "", false
+}
+
+/**
+*  checkMulticlassPrereqs applies the SRD rule: meet the prerequisites of the new class and every current class
+**/
+func checkMulticlassPrereqs(c *Character, class string) error {
+	for _, cl := range append(classLevels(c), ClassLevel{Class: trimLower(class)}) {
+		ci, _ := lookupClass(cl.Class)
+		if req, bad := unmetRequirement(c, ci.Multiclass); bad {
+			return fmt.Errorf("multiclassing into %s needs %s for %s", trimLower(class), req, cl.Class)
+		}
+	}
+	return nil
+}
+
+/**
+*  castingClasses returns the class levels that use the shared slot table (warlock Pact Magic excluded)
+**/
+func castingClasses(c *Character) []ClassLevel {
+	var out []ClassLevel
+	for _, cl := range classLevels(c) {
+		if ct := casterType(cl.Class); ct != "none" && ct != "warlock" {
+			out = append(out, cl)
+		}
+	}
+	return out
+}
+
+/**
+*  multiclassCasterLevel adds full caster levels, half of half-caster levels and a third of third-caster levels
+**/
+func multiclassCasterLevel(classes []ClassLevel) int {
+	total := 0
+	for _, cl := range classes {
+		switch casterType(cl.Class) {
+		case "full":
+			total += cl.Level
+		case "half":
+			total += cl.Level / 2
+		case "third":
+			total += cl.Level / 3
+		}
+	}
+	return min(total, maxCharacterLevel)
+}
+
+/**
+*  characterSpellSlots returns the shared slot table and, when it must be kept separate, warlock Pact Magic slots
+**/
+func characterSpellSlots(c *Character) (map[int]int, map[int]int) {
+	casting := castingClasses(c)
+	warlock := classLevelOf(c, "warlock")
+	var slots map[int]int
+	switch len(casting) {
+	case 0:
+		slots = map[int]int{}
+	case 1:
+		slots = spellSlotsFor(casterType(casting[0].Class), casting[0].Level)
+	default:
+		slots = spellSlotsFor("full", multiclassCasterLevel(casting))
+	}
+	if warlock == 0 {
+		return slots, nil
+	}
+	pact := spellSlotsFor("warlock", warlock)
+	if len(casting) == 0 {
+		return pact, nil
+	}
+	return slots, pact
+}
+
+/**
+*  characterFeatures returns the class features of every class up to its class level
+**/
+func characterFeatures(c *Character) []ClassFeature {
+	var out []ClassFeature
+	for _, cl := range classLevels(c) {
+		out = append(out, classFeaturesBetween(cl.Class, 0, cl.Level)...)
+		if sub := subclassOf(c, cl.Class); sub != "" {
+			out = append(out, subclassFeaturesBetween(cl.Class, sub, 0, cl.Level)...)
+		}
+	}
+	return withFeatureValues(c, out)
+}
+
+/**
+*  cantripsKnownFor returns the cantrips known across all of the character's classes
+**/
+func cantripsKnownFor(c *Character) int {
+	total := 0
+	for _, cl := range classLevels(c) {
+		total += cantripsKnown(cl.Class, cl.Level)
+	}
+	return total
+}
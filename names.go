@@ -0,0 +1,183 @@
+// Layer: Domain (business rules: race-aware name generation from the bundled name lists; no IO)
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	markovOrder     = 2
+	maxNameAttempts = 40
+	defaultNameRace = "human"
+	markovStart     = '^'
+	markovEnd       = '$'
+)
+
+/**
+*  normalizeGender maps a user supplied gender to "male", "female" or "neutral" ("" means neutral)
+**/
+func normalizeGender(g string) (string, error) {
+	switch trimLower(g) {
+	case "", "any", "neutral", "n", "x":
+		return "neutral", nil
+	case "male", "m", "man":
+		return "male", nil
+	case "female", "f", "woman":
+		return "female", nil
+	default:
+		return "", fmt.Errorf("unknown gender %q (use male, female or neutral)", g)
+	}
+}
+
+/**
+*  nameListFor returns the name list of a race, falling back to its parent race ("hill dwarf" -> dwarf)
+**/
+func nameListFor(race string) (NameList, error) {
+	if strings.TrimSpace(race) == "" {
+		race = defaultNameRace
+	}
+	r, err := resolveRace(race)
+	if err != nil {
+		return NameList{}, err
+	}
+	for _, key := range []string{normalizeRaceName(r.Name), normalizeRaceName(r.Parent)} {
+		if nl, ok := csvNameLists[key]; ok && key != "" {
+			return nl, nil
+		}
+	}
+	return NameList{}, fmt.Errorf("no names for race %q (have: %s)", r.Name, strings.Join(nameListRaces(), ", "))
+}
+
+/**
+*  givenNamesFor returns the given names for a gender; neutral draws from every list of the race
+**/
+func givenNamesFor(nl NameList, gender string) []string {
+	if gender == "neutral" {
+		var out []string
+		for _, g := range []string{"male", "female", "neutral"} {
+			out = append(out, nl.Given[g]...)
+		}
+		return out
+	}
+	return append(append([]string(nil), nl.Given[gender]...), nl.Given["neutral"]...)
+}
+
+/**
+*  buildMarkovChain maps every markovOrder-letter prefix of the names to the letters that follow it
+**/
+func buildMarkovChain(names []string) map[string][]rune {
+	chain := map[string][]rune{}
+	for _, n := range names {
+		runes := []rune(strings.Repeat(string(markovStart), markovOrder) + strings.ToLower(n) + string(markovEnd))
+		for i := markovOrder; i < len(runes); i++ {
+			key := string(runes[i-markovOrder : i])
+			chain[key] = append(chain[key], runes[i])
+		}
+	}
+	return chain
+}
+
+/**
+*  markovName walks the chain from the start prefix until it ends or gets too long
+**/
+func markovName(r *rand.Rand, chain map[string][]rune, maxLen int) string {
+	prefix := []rune(strings.Repeat(string(markovStart), markovOrder))
+	var out []rune
+	for len(out) <= maxLen {
+		next := chain[string(prefix)]
+		if len(next) == 0 {
+			break
+		}
+		ch := next[r.Intn(len(next))]
+		if ch == markovEnd {
+			return string(out)
+		}
+		out = append(out, ch)
+		prefix = append(prefix[1:], ch)
+	}
+	return ""
+}
+
+/**
+*  nameLengths returns the shortest and longest name in the list
+**/
+func nameLengths(names []string) (int, int) {
+	lo, hi := 0, 0
+	for i, n := range names {
+		l := len([]rune(n))
+		if i == 0 || l < lo {
+			lo = l
+		}
+		hi = max(hi, l)
+	}
+	return lo, hi
+}
+
+/**
+*  randomGivenName invents a given name from the list's letter patterns; after maxNameAttempts
+*  without a new name of plausible length it picks one from the list
+**/
+func randomGivenName(r *rand.Rand, given []string, chain map[string][]rune) string {
+	lo, hi := nameLengths(given)
+	known := map[string]bool{}
+	for _, n := range given {
+		known[strings.ToLower(n)] = true
+	}
+	for i := 0; i < maxNameAttempts; i++ {
+		n := markovName(r, chain, hi)
+		if l := len([]rune(n)); l >= max(lo, 3) && l <= hi && !known[n] {
+			return titleWords(n)
+		}
+	}
+	return given[r.Intn(len(given))]
+}
+
+/**
+*  randomNames returns count distinct names for the race and gender, with a family name when the race has them
+**/
+func randomNames(r *rand.Rand, race, gender string, count int) ([]string, error) {
+	g, err := normalizeGender(gender)
+	if err != nil {
+		return nil, fieldError("gender", err)
+	}
+	nl, err := nameListFor(race)
+	if err != nil {
+		return nil, fieldError("race", err)
+	}
+	given := givenNamesFor(nl, g)
+	if len(given) == 0 {
+		return nil, fieldError("gender", fmt.Errorf("no %s names for %s", g, nl.Race))
+	}
+	if count < 1 {
+		return nil, fieldError("count", fmt.Errorf("count must be at least 1; %d given", count))
+	}
+	chain := buildMarkovChain(given)
+	seen := map[string]bool{}
+	out := make([]string, 0, count)
+	for tries := 1; len(out) < count; tries++ {
+		name := randomGivenName(r, given, chain)
+		if len(nl.Family) > 0 {
+			name += " " + nl.Family[r.Intn(len(nl.Family))]
+		}
+		if seen[name] && tries < count*maxNameAttempts {
+			continue
+		}
+		seen[name] = true
+		out = append(out, name)
+	}
+	return out, nil
+}
+
+/**
+*  randomName returns one name for the race and gender
+**/
+func randomName(r *rand.Rand, race, gender string) (string, error) {
+	names, err := randomNames(r, race, gender, 1)
+	if err != nil {
+		return "", err
+	}
+	return names[0], nil
+}
@@ -0,0 +1,91 @@
+// Layer: Infrastructure (data source adapter: load the bundled name lists from CSV)
+
+package main
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+)
+
+// the SRD has no name tables; these lists are not SRD content
+const nonSRDNamesFile = "non-SRD-Names.csv"
+
+var (
+	csvNameLists   = map[string]NameList{}
+	namesCSVLoaded = false
+)
+
+/**
+*  tryLoadNames attempts to load the names CSV from the first path that works
+**/
+func tryLoadNames(paths ...string) bool {
+	for _, p := range paths {
+		if err := loadNamesFromCSV(p); err == nil {
+			namesCSVLoaded = true
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	if p := strings.TrimSpace(os.Getenv("NAMES_CSV")); p != "" && tryLoadNames(p) {
+		return
+	}
+	_ = tryLoadNames(catalogPaths(nonSRDNamesFile)...)
+}
+
+/**
+*  loadNamesFromCSV parses the names CSV into per-race given names (by gender) and family names
+**/
+func loadNamesFromCSV(path string) error {
+	rows, err := readCatalogCSV(path)
+	if err != nil {
+		return err
+	}
+	hdr := rows[0]
+	iRace := findColumnIndex(hdr, "race")
+	iGender := findColumnIndex(hdr, "gender")
+	iKind := findColumnIndex(hdr, "kind")
+	iNames := findColumnIndex(hdr, "names")
+	if iRace < 0 || iGender < 0 || iKind < 0 || iNames < 0 {
+		return errors.New("names CSV missing required headers: race, gender, kind, names")
+	}
+
+	tmp := map[string]NameList{}
+	for _, row := range rows[1:] {
+		key := normalizeRaceName(cellAt(row, iRace))
+		if key == "" {
+			continue
+		}
+		nl, ok := tmp[key]
+		if !ok {
+			nl = NameList{Race: cellAt(row, iRace), Given: map[string][]string{}}
+		}
+		names := splitList(cellAt(row, iNames))
+		switch trimLower(cellAt(row, iKind)) {
+		case "family":
+			nl.Family = append(nl.Family, names...)
+		default:
+			gender := trimLower(cellAt(row, iGender))
+			nl.Given[gender] = append(nl.Given[gender], names...)
+		}
+		tmp[key] = nl
+	}
+	csvNameLists = tmp
+	return nil
+}
+
+/**
+*  nameListRaces returns the lowercase races that have bundled name lists
+**/
+func nameListRaces() []string {
+	out := make([]string, 0, len(csvNameLists))
+	for k := range csvNameLists {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
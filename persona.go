@@ -0,0 +1,220 @@
+// Layer: Domain (business rules: personality, alignment and appearance; no IO)
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+const personalityTraitCount = 2
+
+var alignments = []string{
+	"lawful good", "neutral good", "chaotic good",
+	"lawful neutral", "neutral", "chaotic neutral",
+	"lawful evil", "neutral evil", "chaotic evil",
+}
+
+var alignmentAbbreviations = map[string]string{
+	"lg": "lawful good", "ng": "neutral good", "cg": "chaotic good",
+	"ln": "lawful neutral", "n": "neutral", "tn": "neutral", "true neutral": "neutral", "neutral neutral": "neutral", "cn": "chaotic neutral",
+	"le": "lawful evil", "ne": "neutral evil", "ce": "chaotic evil",
+}
+
+var (
+	eyeColors  = []string{"brown", "dark brown", "hazel", "amber", "green", "blue", "grey"}
+	hairColors = []string{"black", "dark brown", "brown", "auburn", "red", "blond", "grey"}
+)
+
+/**
+*  normalizeAlignment maps "LG", "lawful-good" or "true neutral" onto one of the nine alignments
+**/
+func normalizeAlignment(s string) (string, error) {
+	a := strings.Join(strings.Fields(strings.ReplaceAll(trimLower(s), "-", " ")), " ")
+	if full, ok := alignmentAbbreviations[a]; ok {
+		return full, nil
+	}
+	if containsString(alignments, a) {
+		return a, nil
+	}
+	return "", unknownNameError("alignment", strings.TrimSpace(s), alignments)
+}
+
+/**
+*  parseHeight reads 5'10", 5'10, 5 ft 10 or a plain number of inches
+**/
+func parseHeight(s string) (int, error) {
+	h := strings.NewReplacer("\"", "", "in", "", "ft", "'", " ", "").Replace(trimLower(s))
+	if n, err := strconv.Atoi(h); err == nil && n > 0 {
+		return n, nil
+	}
+	ft, in, ok := strings.Cut(h, "'")
+	f, err1 := strconv.Atoi(ft)
+	i := 0
+	var err2 error
+	if in != "" {
+		i, err2 = strconv.Atoi(in)
+	}
+	if !ok || err1 != nil || err2 != nil || f < 0 || i < 0 || i > 11 || f*12+i == 0 {
+		return 0, fmt.Errorf("invalid height %q (use 5'10\" or inches)", s)
+	}
+	return f*12 + i, nil
+}
+
+/**
+*  formatHeight renders inches as 5'10"
+**/
+func formatHeight(inches int) string {
+	return fmt.Sprintf("%d'%d\"", inches/12, inches%12)
+}
+
+/**
+*  alignmentForIdeal rolls an alignment that fits an ideal tagged lawful, chaotic, good, evil, neutral or any;
+*  the untagged axis is never evil
+**/
+func alignmentForIdeal(r *rand.Rand, tag string) string {
+	ethics := []string{"lawful", "neutral", "chaotic"}
+	morals := []string{"good", "neutral"}
+	switch tag {
+	case "lawful", "chaotic":
+		ethics = []string{tag}
+	case "good", "evil":
+		morals = []string{tag}
+	case "neutral":
+		return "neutral"
+	}
+	a := ethics[r.Intn(len(ethics))] + " " + morals[r.Intn(len(morals))]
+	if a == "neutral neutral" {
+		return "neutral"
+	}
+	return a
+}
+
+/**
+*  pickTraitEntry rolls one entry of a table (an empty entry when the table is empty)
+**/
+func pickTraitEntry(r *rand.Rand, table []TraitEntry) TraitEntry {
+	if len(table) == 0 {
+		return TraitEntry{}
+	}
+	return table[r.Intn(len(table))]
+}
+
+/**
+*  rollAppearance rolls age, height (inches), weight (lb), eyes and hair from the race's tables (common eye and
+*  hair colors when the race lists none)
+**/
+func rollAppearance(r *rand.Rand, race string) (Persona, error) {
+	var p Persona
+	info, _ := lookupRace(race)
+	if info.AgeAdult > 0 {
+		p.Age = info.AgeAdult + r.Intn(max(1, (info.AgeMax-info.AgeAdult)/2))
+	}
+	if info.HeightBase > 0 {
+		heightRoll, err := rollDiceExpr(r, info.HeightMod)
+		if err != nil {
+			return Persona{}, err
+		}
+		weightRoll, err := rollDiceExpr(r, info.WeightMod)
+		if err != nil {
+			return Persona{}, err
+		}
+		p.Height = info.HeightBase + heightRoll
+		p.Weight = info.WeightBase + heightRoll*weightRoll
+	}
+	eyes, hair := eyeColors, hairColors
+	if len(info.Eyes) > 0 {
+		eyes = info.Eyes
+	}
+	if len(info.Hair) > 0 {
+		hair = info.Hair
+	}
+	p.Eyes = eyes[r.Intn(len(eyes))]
+	p.Hair = hair[r.Intn(len(hair))]
+	return p, nil
+}
+
+/**
+*  rollPersona rolls personality traits, ideal, bond and flaw from the background tables, an alignment
+*  that fits the ideal, and the race's appearance
+**/
+func rollPersona(c *Character, seed int64) (Persona, error) {
+	r := newRoller(seed)
+	p, err := rollAppearance(r, c.Race)
+	if err != nil {
+		return Persona{}, err
+	}
+	traits := backgroundTraitTable(c.Background, "trait")
+	for _, i := range r.Perm(len(traits))[:min(personalityTraitCount, len(traits))] {
+		p.PersonalityTraits = append(p.PersonalityTraits, traits[i].Text)
+	}
+	ideal := pickTraitEntry(r, backgroundTraitTable(c.Background, "ideal"))
+	p.Ideal = ideal.Text
+	p.Alignment = alignmentForIdeal(r, ideal.Alignment)
+	p.Bond = pickTraitEntry(r, backgroundTraitTable(c.Background, "bond")).Text
+	p.Flaw = pickTraitEntry(r, backgroundTraitTable(c.Background, "flaw")).Text
+	return p, nil
+}
+
+/**
+*  overrideText replaces *dst with v unless v is blank
+**/
+func overrideText(dst *string, v string) {
+	if v = strings.TrimSpace(v); v != "" {
+		*dst = v
+	}
+}
+
+/**
+*  applyPersona sets the given fields on c; with roll everything else is re-rolled from seed (kept as PersonaSeed), without it
+*  the other fields keep their current values
+**/
+func applyPersona(c *Character, given Persona, roll bool, seed int64) error {
+	if strings.TrimSpace(given.Alignment) != "" {
+		a, err := normalizeAlignment(given.Alignment)
+		if err != nil {
+			return fieldError("alignment", err)
+		}
+		given.Alignment = a
+	}
+	if given.Age < 0 {
+		return fieldError("age", fmt.Errorf("age can't be negative"))
+	}
+	if given.Height < 0 {
+		return fieldError("height", fmt.Errorf("height can't be negative"))
+	}
+	if given.Weight < 0 {
+		return fieldError("weight", fmt.Errorf("weight can't be negative"))
+	}
+	p := c.Persona
+	if roll {
+		rolled, err := rollPersona(c, seed)
+		if err != nil {
+			return err
+		}
+		p = rolled
+		c.PersonaSeed = seed
+	}
+	if len(given.PersonalityTraits) > 0 {
+		p.PersonalityTraits = given.PersonalityTraits
+	}
+	overrideText(&p.Alignment, given.Alignment)
+	overrideText(&p.Ideal, given.Ideal)
+	overrideText(&p.Bond, given.Bond)
+	overrideText(&p.Flaw, given.Flaw)
+	overrideText(&p.Eyes, given.Eyes)
+	overrideText(&p.Hair, given.Hair)
+	if given.Age > 0 {
+		p.Age = given.Age
+	}
+	if given.Height > 0 {
+		p.Height = given.Height
+	}
+	if given.Weight > 0 {
+		p.Weight = given.Weight
+	}
+	c.Persona = p
+	return nil
+}
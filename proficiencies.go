@@ -0,0 +1,228 @@
+// Layer: Domain (business rules: language and tool proficiencies from race, class and background; no IO)
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+const (
+	kindLanguage = "language"
+	kindTool     = "tool"
+	anyGrant     = "any"
+)
+
+/**
+*  catalogEntry returns the catalog spelling and category (language type or tool category) of a name
+**/
+func catalogEntry(kind, name string) (string, string, bool) {
+	if kind == kindLanguage {
+		l, ok := lookupLanguage(name)
+		return l.Name, l.Type, ok
+	}
+	t, ok := lookupTool(name)
+	return t.Name, t.Category, ok
+}
+
+/**
+*  catalogOptions returns every name of the language or tool catalog in catalog order
+**/
+func catalogOptions(kind string) []string {
+	var out []string
+	if kind == kindLanguage {
+		for _, l := range csvLanguages {
+			out = append(out, l.Name)
+		}
+		return out
+	}
+	for _, t := range csvTools {
+		out = append(out, t.Name)
+	}
+	return out
+}
+
+/**
+*  isChoiceGrant reports whether a grant is a free pick ("any", "any gaming set", "Lute/Drum") rather than a fixed name
+**/
+func isChoiceGrant(g string) bool {
+	g = trimLower(g)
+	return g == anyGrant || strings.HasPrefix(g, anyGrant+" ") || strings.Contains(g, "/")
+}
+
+/**
+*  slotAccepts reports whether name may fill a free pick: "any" takes any non-secret language or any tool,
+*  "any <category>" a tool of that category, and "/" separates alternatives
+**/
+func slotAccepts(kind, slot, name string) bool {
+	_, category, ok := catalogEntry(kind, name)
+	if !ok {
+		return false
+	}
+	for _, alt := range strings.Split(slot, "/") {
+		alt = trimLower(alt)
+		switch {
+		case alt == anyGrant:
+			if kind == kindTool || category != "secret" {
+				return true
+			}
+		case strings.HasPrefix(alt, anyGrant+" "):
+			if category == strings.TrimPrefix(alt, anyGrant+" ") {
+				return true
+			}
+		case alt == trimLower(name):
+			return true
+		}
+	}
+	return false
+}
+
+/**
+*  languageGrants lists the languages race and background give, free picks included
+**/
+func languageGrants(c *Character) []string {
+	r, _ := lookupRace(c.Race)
+	bg, _ := lookupBackground(c.Background)
+	return append(append([]string(nil), r.Languages...), bg.Languages...)
+}
+
+/**
+*  toolGrants lists the tool proficiencies race, background and (first) class give, free picks included
+**/
+func toolGrants(c *Character) []string {
+	r, _ := lookupRace(c.Race)
+	bg, _ := lookupBackground(c.Background)
+	ci, _ := lookupClass(c.Class)
+	return append(append(append([]string(nil), r.Tools...), bg.Tools...), ci.Tools...)
+}
+
+/**
+*  grantedProficiencies resolves grants into names: fixed grants are taken as is (a duplicate turns into a
+*  free pick), picks fill the free slots, and slots left open get the first fitting option (random with r)
+**/
+func grantedProficiencies(kind string, grants, picks []string, r *rand.Rand) ([]string, error) {
+	var out, slots []string
+	for _, g := range grants {
+		if isChoiceGrant(g) {
+			slots = append(slots, g)
+			continue
+		}
+		name := strings.TrimSpace(g)
+		if canonical, _, ok := catalogEntry(kind, name); ok {
+			name = canonical
+		}
+		if containsString(out, name) {
+			slots = append(slots, anyGrant)
+			continue
+		}
+		out = append(out, name)
+	}
+
+	filled := make([]bool, len(slots))
+	for i, p := range picks {
+		field := fmt.Sprintf("%ss[%d]", kind, i)
+		name, _, ok := catalogEntry(kind, p)
+		if !ok {
+			return nil, fieldError(field, unknownNameError(kind, strings.TrimSpace(p), catalogOptions(kind)))
+		}
+		if containsString(out, name) {
+			return nil, fieldError(field, fmt.Errorf("%s is already known", name))
+		}
+		idx := -1
+		for pass := 0; pass < 2 && idx < 0; pass++ {
+			for j, s := range slots {
+				if !filled[j] && (trimLower(s) == anyGrant) == (pass == 1) && slotAccepts(kind, s, name) {
+					idx = j
+					break
+				}
+			}
+		}
+		if idx < 0 {
+			return nil, fieldError(field, fmt.Errorf("%s doesn't fit a free %s pick (open: %s)", name, kind, formatOpenSlots(slots, filled)))
+		}
+		filled[idx] = true
+		out = append(out, name)
+	}
+
+	for j, s := range slots {
+		if filled[j] {
+			continue
+		}
+		var options []string
+		for _, o := range catalogOptions(kind) {
+			if slotAccepts(kind, s, o) && !containsString(out, o) {
+				options = append(options, o)
+			}
+		}
+		switch {
+		case len(options) == 0:
+			continue
+		case r != nil:
+			out = append(out, options[r.Intn(len(options))])
+		default:
+			out = append(out, options[0])
+		}
+	}
+	return out, nil
+}
+
+/**
+*  freePicks returns the entries of have that came from free picks rather than fixed grants
+**/
+func freePicks(kind string, grants, have []string) []string {
+	fixed := map[string]bool{}
+	for _, g := range grants {
+		if !isChoiceGrant(g) {
+			fixed[trimLower(g)] = true
+		}
+	}
+	var out []string
+	for _, h := range have {
+		if !fixed[trimLower(h)] {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+/**
+*  formatOpenSlots lists the free picks not filled yet, or "none"
+**/
+func formatOpenSlots(slots []string, filled []bool) string {
+	var open []string
+	for j, s := range slots {
+		if !filled[j] {
+			open = append(open, trimLower(s))
+		}
+	}
+	if len(open) == 0 {
+		return "none"
+	}
+	return strings.Join(open, ", ")
+}
+
+/**
+*  assignProficiencies fills c's languages and tools from its race, class and background plus the picks
+**/
+func assignProficiencies(c *Character, languages, tools []string, r *rand.Rand) error {
+	langs, err := grantedProficiencies(kindLanguage, languageGrants(c), languages, r)
+	if err != nil {
+		return err
+	}
+	tls, err := grantedProficiencies(kindTool, toolGrants(c), tools, r)
+	if err != nil {
+		return err
+	}
+	c.Languages, c.Tools = langs, tls
+	return nil
+}
+
+/**
+*  ensureProficiencies fills languages and tools with the default picks for characters saved before they existed
+**/
+func ensureProficiencies(c *Character) {
+	if c.Languages == nil && c.Tools == nil {
+		_ = assignProficiencies(c, nil, nil, nil)
+	}
+}
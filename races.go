@@ -0,0 +1,89 @@
+// Layer: Domain (business rules: races, subraces and racial bonuses; no IO)
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+/**
+*  normalizeRaceName lowercases a race name and treats hyphens as spaces ("Half-Orc" -> "half orc")
+**/
+func normalizeRaceName(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(s, "-", " "))), " ")
+}
+
+/**
+*  resolveRace validates a race against the catalog; an empty race is allowed and has no traits
+**/
+func resolveRace(name string) (Race, error) {
+	if strings.TrimSpace(name) == "" {
+		return Race{}, nil
+	}
+	if r, ok := lookupRace(name); ok {
+		return r, nil
+	}
+	return Race{}, unknownNameError("race", strings.TrimSpace(name), raceNames())
+}
+
+/**
+*  raceBonusesFor returns the racial ability deltas, resolving flexible bonuses from choices or the priority list
+**/
+func raceBonusesFor(r Race, choices, priority []string) (AbilityScores, []string, error) {
+	if r.ChoiceCount == 0 {
+		if len(choices) > 0 {
+			return AbilityScores{}, nil, fmt.Errorf("race %q has no ability bonus of your choice", r.Name)
+		}
+		return r.Bonuses, nil, nil
+	}
+	excluded := map[int]bool{}
+	for _, ab := range r.ChoiceExclude {
+		excluded[abilityIndex(ab)] = true
+	}
+	picked := make([]string, 0, r.ChoiceCount)
+	seen := map[int]bool{}
+	if len(choices) == 0 {
+		for _, ab := range priority {
+			idx := abilityIndex(ab)
+			if idx >= 0 && !excluded[idx] && len(picked) < r.ChoiceCount {
+				seen[idx] = true
+				picked = append(picked, abilityShortNames[idx])
+			}
+		}
+	}
+	for _, ab := range choices {
+		idx := abilityIndex(ab)
+		switch {
+		case idx < 0:
+			return AbilityScores{}, nil, fmt.Errorf("unknown ability %q in race bonus", ab)
+		case excluded[idx]:
+			return AbilityScores{}, nil, fmt.Errorf("%s cannot take its flexible bonus in %s", r.Name, strings.ToUpper(abilityShortNames[idx]))
+		case seen[idx]:
+			return AbilityScores{}, nil, fmt.Errorf("ability %q chosen twice for race bonus", ab)
+		}
+		seen[idx] = true
+		picked = append(picked, abilityShortNames[idx])
+	}
+	if len(picked) != r.ChoiceCount {
+		return AbilityScores{}, nil, fmt.Errorf("%s gets +%d to %d abilities of your choice; %d given", r.Name, r.ChoiceAmount, r.ChoiceCount, len(picked))
+	}
+	arr := abilityArray(r.Bonuses)
+	for _, ab := range picked {
+		arr[abilityIndex(ab)] += r.ChoiceAmount
+	}
+	return abilitiesFromArray(arr), picked, nil
+}
+
+/**
+*  formatBonuses renders non-zero ability deltas as "STR +2, CHA +1"
+**/
+func formatBonuses(b AbilityScores) string {
+	var parts []string
+	for i, v := range abilityArray(b) {
+		if v != 0 {
+			parts = append(parts, fmt.Sprintf("%s %+d", strings.ToUpper(abilityShortNames[i]), v))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
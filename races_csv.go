@@ -0,0 +1,210 @@
+// Layer: Infrastructure (data source adapter: load races and subraces from CSV)
+
+package main
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultRacesFile = "5e-SRD-Races.csv"
+	// subraces from outside SRD 5.1 (PHB), kept apart from the SRD catalog
+	nonSRDRacesFile = "non-SRD-Races.csv"
+)
+
+var (
+	csvRaces       = map[string]Race{}
+	racesCSVLoaded = false
+)
+
+/**
+*  tryLoadRaces adds the races CSV from the first path that works to the catalog; names already in the catalog are kept
+**/
+func tryLoadRaces(paths ...string) bool {
+	for _, p := range paths {
+		if err := loadRacesFromCSV(p); err == nil {
+			racesCSVLoaded = true
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	if p := strings.TrimSpace(os.Getenv("RACES_CSV")); p == "" || !tryLoadRaces(p) {
+		_ = tryLoadRaces(catalogPaths(defaultRacesFile)...)
+	}
+	if p := strings.TrimSpace(os.Getenv("NON_SRD_RACES_CSV")); p == "" || !tryLoadRaces(p) {
+		_ = tryLoadRaces(catalogPaths(nonSRDRacesFile)...)
+	}
+}
+
+func atoiCell(row []string, i int) int {
+	n, _ := strconv.Atoi(cellAt(row, i))
+	return n
+}
+
+/**
+*  mergeSubrace layers a subrace row on top of its parent race
+**/
+func mergeSubrace(parent, sub Race) Race {
+	out := parent
+	out.Name = sub.Name
+	out.Parent = parent.Name
+	p, s := abilityArray(parent.Bonuses), abilityArray(sub.Bonuses)
+	for i := range p {
+		p[i] += s[i]
+	}
+	out.Bonuses = abilitiesFromArray(p)
+	if sub.Size != "" {
+		out.Size = sub.Size
+	}
+	if sub.Speed != 0 {
+		out.Speed = sub.Speed
+	}
+	if sub.Darkvision != 0 {
+		out.Darkvision = sub.Darkvision
+	}
+	out.Languages = append(append([]string(nil), parent.Languages...), sub.Languages...)
+	out.Traits = append(append([]string(nil), parent.Traits...), sub.Traits...)
+	out.Tools = append(append([]string(nil), parent.Tools...), sub.Tools...)
+	if sub.AgeAdult != 0 {
+		out.AgeAdult, out.AgeMax = sub.AgeAdult, sub.AgeMax
+	}
+	if sub.HeightBase != 0 {
+		out.HeightBase, out.HeightMod = sub.HeightBase, sub.HeightMod
+	}
+	if sub.WeightBase != 0 {
+		out.WeightBase = sub.WeightBase
+	}
+	if sub.WeightMod != "" {
+		out.WeightMod = sub.WeightMod
+	}
+	if len(sub.Eyes) > 0 {
+		out.Eyes = sub.Eyes
+	}
+	if len(sub.Hair) > 0 {
+		out.Hair = sub.Hair
+	}
+	if sub.ChoiceCount > 0 {
+		out.ChoiceCount, out.ChoiceAmount, out.ChoiceExclude = sub.ChoiceCount, sub.ChoiceAmount, sub.ChoiceExclude
+	}
+	return out
+}
+
+/**
+*  loadRacesFromCSV parses a races CSV and adds its new names to the catalog; subrace rows inherit everything
+*  from their parent row, which may come from a file loaded earlier
+**/
+func loadRacesFromCSV(path string) error {
+	rows, err := readCatalogCSV(path)
+	if err != nil {
+		return err
+	}
+	hdr := rows[0]
+	iName := findColumnIndex(hdr, "name")
+	if iName < 0 {
+		return errors.New("races CSV missing required header: name")
+	}
+	iParent := findColumnIndex(hdr, "parent")
+	iAb := make([]int, len(abilityShortNames))
+	for i, ab := range abilityShortNames {
+		iAb[i] = findColumnIndex(hdr, ab)
+	}
+	iSize := findColumnIndex(hdr, "size")
+	iSpeed := findColumnIndex(hdr, "speed")
+	iDark := findColumnIndex(hdr, "darkvision")
+	iLangs := findColumnIndex(hdr, "languages")
+	iTraits := findColumnIndex(hdr, "traits")
+	iChoice := findColumnIndex(hdr, "choice_count")
+	iChoiceAmt := findColumnIndex(hdr, "choice_amount")
+	iChoiceEx := findColumnIndex(hdr, "choice_exclude")
+	iAgeAdult := findColumnIndex(hdr, "age_adult")
+	iAgeMax := findColumnIndex(hdr, "age_max")
+	iHeight := findColumnIndex(hdr, "height_base")
+	iHeightMod := findColumnIndex(hdr, "height_mod")
+	iWeight := findColumnIndex(hdr, "weight_base")
+	iWeightMod := findColumnIndex(hdr, "weight_mod")
+	iTools := findColumnIndex(hdr, "tools")
+	iEyes := findColumnIndex(hdr, "eyes")
+	iHair := findColumnIndex(hdr, "hair")
+
+	base := make(map[string]Race, len(csvRaces)+len(rows))
+	for k, r := range csvRaces {
+		base[k] = r
+	}
+	var subs []Race
+	for _, row := range rows[1:] {
+		name := cellAt(row, iName)
+		if name == "" {
+			continue
+		}
+		var bonus [6]int
+		for i, col := range iAb {
+			bonus[i] = atoiCell(row, col)
+		}
+		r := Race{
+			Name:          name,
+			Parent:        cellAt(row, iParent),
+			Bonuses:       abilitiesFromArray(bonus),
+			Size:          cellAt(row, iSize),
+			Speed:         atoiCell(row, iSpeed),
+			Darkvision:    atoiCell(row, iDark),
+			Languages:     splitList(cellAt(row, iLangs)),
+			Traits:        splitList(cellAt(row, iTraits)),
+			ChoiceCount:   atoiCell(row, iChoice),
+			ChoiceAmount:  atoiCell(row, iChoiceAmt),
+			ChoiceExclude: splitList(cellAt(row, iChoiceEx)),
+			AgeAdult:      atoiCell(row, iAgeAdult),
+			AgeMax:        atoiCell(row, iAgeMax),
+			HeightBase:    atoiCell(row, iHeight),
+			HeightMod:     cellAt(row, iHeightMod),
+			WeightBase:    atoiCell(row, iWeight),
+			WeightMod:     cellAt(row, iWeightMod),
+			Tools:         splitList(cellAt(row, iTools)),
+			Eyes:          splitList(cellAt(row, iEyes)),
+			Hair:          splitList(cellAt(row, iHair)),
+		}
+		if _, ok := base[normalizeRaceName(name)]; ok {
+			continue
+		}
+		if r.Parent != "" {
+			subs = append(subs, r)
+			continue
+		}
+		base[normalizeRaceName(name)] = r
+	}
+	for _, sub := range subs {
+		parent, ok := base[normalizeRaceName(sub.Parent)]
+		if !ok {
+			return errors.New("races CSV: subrace " + sub.Name + " has unknown parent " + sub.Parent)
+		}
+		base[normalizeRaceName(sub.Name)] = mergeSubrace(parent, sub)
+	}
+	csvRaces = base
+	return nil
+}
+
+/**
+*  lookupRace returns the catalog entry for a race or subrace name
+**/
+func lookupRace(name string) (Race, bool) {
+	r, ok := csvRaces[normalizeRaceName(name)]
+	return r, ok
+}
+
+/**
+*  raceNames returns the sorted normalized race and subrace names in the catalog
+**/
+func raceNames() []string {
+	out := make([]string, 0, len(csvRaces))
+	for k := range csvRaces {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
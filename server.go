@@ -4,30 +4,205 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 )
 
 type createRequest struct {
 	Name          string         `json:"name"`
+	Gender        string         `json:"gender,omitempty"`
 	Race          string         `json:"race"`
+	RaceBonus     []string       `json:"race_bonus,omitempty"`
 	Class         string         `json:"class"`
 	Level         int            `json:"level"`
 	Background    string         `json:"background"`
 	AbilityScores *AbilityScores `json:"ability_scores,omitempty"`
+	Method        string         `json:"method,omitempty"`
+	Seed          int64          `json:"seed,omitempty"`
+	Assign        string         `json:"assign,omitempty"`
+	Priority      []string       `json:"priority,omitempty"`
+	HPMethod      string         `json:"hp_method,omitempty"`
+	HPSeed        int64          `json:"hp_seed,omitempty"`
 	Skills        []string       `json:"skills,omitempty"`
+	Expertise     []string       `json:"expertise,omitempty"`
+	Languages     []string       `json:"languages,omitempty"`
+	Tools         []string       `json:"tools,omitempty"`
+	Subclass      string         `json:"subclass,omitempty"`
 	Weapon        string         `json:"weapon,omitempty"`
 	Armor         string         `json:"armor,omitempty"`
 	Shield        string         `json:"shield,omitempty"`
 	OffHand       string         `json:"offhand,omitempty"`
+	StartingGold  string         `json:"starting_gold,omitempty"`
+	GoldSeed      int64          `json:"gold_seed,omitempty"`
+	EquipChoices  []string       `json:"equipment,omitempty"`
+	NoEquipment   bool           `json:"no_equipment,omitempty"`
+	XP            *int           `json:"xp,omitempty"`
+	Update        bool           `json:"update,omitempty"`
+	personaFields
+	RollPersona bool  `json:"roll_persona,omitempty"`
+	PersonaSeed int64 `json:"persona_seed,omitempty"`
+}
+
+type personaFields struct {
+	Alignment         string   `json:"alignment,omitempty"`
+	PersonalityTraits []string `json:"personality_traits,omitempty"`
+	Ideal             string   `json:"ideal,omitempty"`
+	Bond              string   `json:"bond,omitempty"`
+	Flaw              string   `json:"flaw,omitempty"`
+	Age               int      `json:"age,omitempty"`
+	Height            int      `json:"height,omitempty"`
+	Weight            int      `json:"weight,omitempty"`
+	Eyes              string   `json:"eyes,omitempty"`
+	Hair              string   `json:"hair,omitempty"`
+}
+
+type proficienciesRequest struct {
+	Name      string   `json:"name"`
+	Languages []string `json:"languages,omitempty"`
+	Tools     []string `json:"tools,omitempty"`
+}
+
+type inventoryRequest struct {
+	Name     string   `json:"name"`
+	Item     string   `json:"item"`
+	Quantity int      `json:"quantity,omitempty"`
+	Weight   *float64 `json:"weight,omitempty"`
+	Notes    string   `json:"notes,omitempty"`
+	Equipped bool     `json:"equipped,omitempty"`
+	Attuned  bool     `json:"attuned,omitempty"`
+}
+
+type tradeRequest struct {
+	Name     string `json:"name"`
+	Item     string `json:"item"`
+	Quantity int    `json:"quantity,omitempty"`
+}
+
+type coinsRequest struct {
+	Name  string `json:"name"`
+	Add   string `json:"add,omitempty"`
+	Spend string `json:"spend,omitempty"`
+}
+
+type equipRequest struct {
+	Name    string `json:"name"`
+	Weapon  string `json:"weapon,omitempty"`
+	OffHand string `json:"offhand,omitempty"`
+	Armor   string `json:"armor,omitempty"`
+	Shield  string `json:"shield,omitempty"`
+	Replace bool   `json:"replace,omitempty"`
+}
+
+type slotRequest struct {
+	Name string `json:"name"`
+	Slot string `json:"slot,omitempty"`
+}
+
+type equipResponse struct {
+	characterResponse
+	Displaced []string `json:"displaced,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+type inventoryResponse struct {
+	Name        string      `json:"name"`
+	Items       []Item      `json:"items"`
+	Encumbrance Encumbrance `json:"encumbrance"`
+}
+
+type personaRequest struct {
+	Name string `json:"name"`
+	Roll bool   `json:"roll,omitempty"`
+	Seed int64  `json:"seed,omitempty"`
+	personaFields
+}
+
+type characterResponse struct {
+	Character
+	RaceInfo          *Race          `json:"race_info,omitempty"`
+	ClassInfo         *ClassInfo     `json:"class_info,omitempty"`
+	Derived           DerivedStats   `json:"derived"`
+	Encumbrance       Encumbrance    `json:"encumbrance"`
+	PointBuyRemaining *int           `json:"point_buy_remaining,omitempty"`
+	Features          []ClassFeature `json:"features,omitempty"`
+	ClassLevels       []ClassLevel   `json:"class_levels,omitempty"`
+}
+
+type namesResponse struct {
+	Race   string   `json:"race"`
+	Gender string   `json:"gender"`
+	Seed   int64    `json:"seed"`
+	Names  []string `json:"names"`
+}
+
+type levelUpRequest struct {
+	Name      string   `json:"name"`
+	Class     string   `json:"class,omitempty"`
+	To        int      `json:"to,omitempty"`
+	HPMethod  string   `json:"hp_method,omitempty"`
+	HPSeed    int64    `json:"hp_seed,omitempty"`
+	Spells    []string `json:"spells,omitempty"`
+	Expertise []string `json:"expertise,omitempty"`
+	ASI       []string `json:"asi,omitempty"`
+	Feat      string   `json:"feat,omitempty"`
+	Subclass  string   `json:"subclass,omitempty"`
+}
+
+type awardRequest struct {
+	Name     string   `json:"name,omitempty"`
+	Chars    []string `json:"chars,omitempty"`
+	XP       int      `json:"xp"`
+	LevelUp  bool     `json:"levelup,omitempty"`
+	HPMethod string   `json:"hp_method,omitempty"`
+}
+
+type subclassRequest struct {
+	Name     string `json:"name"`
+	Class    string `json:"class,omitempty"`
+	Subclass string `json:"subclass"`
+}
+
+type improveRequest struct {
+	Name  string   `json:"name"`
+	Class string   `json:"class,omitempty"`
+	Level int      `json:"level,omitempty"`
+	ASI   []string `json:"asi,omitempty"`
+	Feat  string   `json:"feat,omitempty"`
+}
+
+type levelUpResponse struct {
+	Character characterResponse `json:"character"`
+	LevelUp   LevelUpResult     `json:"level_up"`
 }
 
 type apiError struct {
-	Error string `json:"error"`
+	Error  string            `json:"error"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+/**
+*  newAPIError converts an error into an API error, keeping field-level details when present
+**/
+func newAPIError(err error) apiError {
+	out := apiError{Error: err.Error()}
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		out.Fields = make(map[string]string, len(ve.Fields))
+		for _, f := range ve.Fields {
+			if prev, ok := out.Fields[f.Field]; ok {
+				out.Fields[f.Field] = prev + "; " + f.Message
+			} else {
+				out.Fields[f.Field] = f.Message
+			}
+		}
+	}
+	return out
 }
 
 /**
@@ -43,11 +218,15 @@ func writeJSON(w http.ResponseWriter, status int, v any) {
 func handleCharactersGet(w http.ResponseWriter, r *http.Request) {
 	name := strings.TrimSpace(r.URL.Query().Get("name"))
 	if name == "" {
-		writeJSON(w, http.StatusOK, characters)
+		out := make([]characterResponse, 0, len(characters))
+		for i := range characters {
+			out = append(out, newCharacterResponse(&characters[i]))
+		}
+		writeJSON(w, http.StatusOK, out)
 		return
 	}
 	if c := findCharLike(name); c != nil {
-		writeJSON(w, http.StatusOK, c)
+		writeJSON(w, http.StatusOK, newCharacterResponse(c))
 		return
 	}
 	writeJSON(w, http.StatusNotFound, apiError{Error: "character not found"})
@@ -61,14 +240,62 @@ func handleCharactersPost(w http.ResponseWriter, r *http.Request) {
 	}
 	req.Name = strings.TrimSpace(req.Name)
 	if req.Name == "" {
-		writeJSON(w, http.StatusBadRequest, apiError{Error: "name is required"})
-		return
+		name, err := randomName(newRoller(newSeed()), req.Race, req.Gender)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, newAPIError(err))
+			return
+		}
+		req.Name = uniqueCharacterName(name)
 	}
 	if req.Level < 1 {
 		req.Level = 1
 	}
 
-	c := buildCharacterFromRequest(req)
+	c, err := buildCharacterFromRequest(req)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(err))
+		return
+	}
+	if req.XP != nil {
+		if *req.XP < 0 {
+			writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("xp", errors.New("xp can't be negative"))))
+			return
+		}
+		c.XP = *req.XP
+	}
+	setBaseScores(&c)
+	if strings.TrimSpace(req.Subclass) != "" {
+		if _, err := chooseSubclass(&c, "", req.Subclass); err != nil {
+			writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("subclass", err)))
+			return
+		}
+	}
+	hpSeed := req.HPSeed
+	if hpSeed == 0 {
+		hpSeed = newSeed()
+	}
+	if err := gainHitDice(&c, req.HPMethod, hpSeed); err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("hp_method", err)))
+		return
+	}
+	recalcHP(&c)
+	if c.Expertise, err = validateExpertise(&c, req.Expertise); err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("expertise", err)))
+		return
+	}
+	personaSeed := req.PersonaSeed
+	if personaSeed == 0 {
+		personaSeed = newSeed()
+	}
+	if err := applyPersona(&c, req.persona(), req.RollPersona, personaSeed); err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(err))
+		return
+	}
+	if err := assignProficiencies(&c, req.Languages, req.Tools, nil); err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(err))
+		return
+	}
+	ensureEquipmentInInventory(&c)
 
 	idx := -1
 	for i := range characters {
@@ -77,6 +304,38 @@ func handleCharactersPost(w http.ResponseWriter, r *http.Request) {
 			break
 		}
 	}
+	if req.Update && idx >= 0 {
+		if err := keepProgress(&c, characters[idx], req); err != nil {
+			writeJSON(w, http.StatusBadRequest, newAPIError(err))
+			return
+		}
+		characters[idx] = c
+		EnrichCharacter(&c)
+		saveCharacters()
+		writeJSON(w, http.StatusOK, newCharacterResponse(&c))
+		return
+	}
+	if req.StartingGold != "" && len(req.EquipChoices) > 0 {
+		writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("starting_gold", errors.New("starting gold replaces the starting equipment; send starting_gold or equipment, not both"))))
+		return
+	}
+	if req.StartingGold == "" && !req.NoEquipment {
+		if err := applyStartingEquipment(&c, req.EquipChoices, nil); err != nil {
+			writeJSON(w, http.StatusBadRequest, newAPIError(err))
+			return
+		}
+	}
+	if req.StartingGold != "" {
+		goldSeed := req.GoldSeed
+		if goldSeed == 0 {
+			goldSeed = newSeed()
+		}
+		if _, err := grantStartingGold(&c, req.StartingGold, newRoller(goldSeed)); err != nil {
+			writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("starting_gold", err)))
+			return
+		}
+	}
+
 	if idx >= 0 {
 		characters[idx] = c
 	} else {
@@ -86,7 +345,643 @@ func handleCharactersPost(w http.ResponseWriter, r *http.Request) {
 	EnrichCharacter(&c)
 
 	saveCharacters()
-	writeJSON(w, http.StatusCreated, c)
+	resp := newCharacterResponse(&c)
+	if left, ok := pointBuyLeftFor(req); ok {
+		resp.PointBuyRemaining = &left
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+/**
+*  keepProgress carries what an update request (the sheet's re-save) doesn't send from the stored character into
+*  the rebuilt one; progress tied to the class is only kept while the class and level are unchanged. Starting
+*  gear is refused, and slots the request names are equipped over the stored ones
+**/
+func keepProgress(c *Character, old Character, req createRequest) error {
+	var verr ValidationError
+	if req.StartingGold != "" {
+		verr.add("starting_gold", "starting gold only applies to new characters")
+	}
+	if len(req.EquipChoices) > 0 {
+		verr.add("equipment", "starting equipment only applies to new characters")
+	}
+	if err := verr.errOrNil(); err != nil {
+		return err
+	}
+	sent := c.Equipment
+	c.Equipment = old.Equipment
+	if !req.RollPersona {
+		c.PersonaSeed = old.PersonaSeed
+	}
+	c.Purse = old.Purse
+	c.Inventory = old.Inventory
+	if len(req.Languages) == 0 {
+		c.Languages = old.Languages
+	}
+	if len(req.Tools) == 0 {
+		c.Tools = old.Tools
+	}
+	if req.XP == nil {
+		c.XP = old.XP
+	}
+	if len(req.Expertise) == 0 {
+		c.Expertise = nil
+		for _, s := range old.Expertise {
+			if hasSkill(c, s) {
+				c.Expertise = append(c.Expertise, s)
+			}
+		}
+	}
+	if strings.EqualFold(c.Class, old.Class) && c.Level == old.Level {
+		c.HPMethod, c.HPSeed, c.HitDieRolls = old.HPMethod, old.HPSeed, old.HitDieRolls
+		c.MaxHP, c.CurrentHP, c.TempHP = old.MaxHP, old.CurrentHP, old.TempHP
+		recalcHP(c)
+		c.Improvements = old.Improvements
+		c.BaseAbilityScores = addScores(old.BaseAbilityScores, subScores(c.AbilityScores, old.AbilityScores))
+		c.Classes = old.Classes
+		c.Subclasses = old.Subclasses
+		c.Spellcasting, c.AbilityRoll = old.Spellcasting, old.AbilityRoll
+	}
+	for _, slot := range []string{slotMain, slotOff, slotArmor, slotShield} {
+		if item := *slotItem(&sent, slot); item != "" {
+			if _, err := equipSlot(c, slot, item, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/**
+*  newCharacterResponse wraps a character with the catalog data the sheet needs
+**/
+func newCharacterResponse(c *Character) characterResponse {
+	resp := characterResponse{Character: *c, Derived: computeDerivedStats(c), Encumbrance: encumbranceFor(c, false)}
+	if r, ok := lookupRace(c.Race); ok {
+		if bonus, _, err := raceBonusesFor(r, c.RaceChoices, nil); err == nil {
+			r.Bonuses = bonus
+		}
+		resp.RaceInfo = &r
+	}
+	if ci, ok := lookupClass(c.Class); ok {
+		resp.ClassInfo = &ci
+	}
+	resp.Features = characterFeatures(c)
+	resp.ClassLevels = classLevels(c)
+	return resp
+}
+
+/**
+*  apiLevelUpHandler handles POST /api/characters/levelup
+**/
+func apiLevelUpHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req levelUpRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: "invalid json"})
+		return
+	}
+	c := findCharLike(strings.TrimSpace(req.Name))
+	if c == nil {
+		writeJSON(w, http.StatusNotFound, apiError{Error: "character not found"})
+		return
+	}
+	choices := LevelUpChoices{
+		Class:     req.Class,
+		Spells:    req.Spells,
+		Expertise: req.Expertise,
+		ASI:       req.ASI,
+		Feat:      req.Feat,
+		Subclass:  req.Subclass,
+	}
+	updated, res, err := levelUp(*c, req.To, req.HPMethod, req.HPSeed, choices)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(err))
+		return
+	}
+	*c = updated
+	saveCharacters()
+	writeJSON(w, http.StatusOK, levelUpResponse{Character: newCharacterResponse(c), LevelUp: res})
+}
+
+/**
+*  apiNamesHandler handles GET /api/names?race=elf&gender=female&count=10&seed=N
+**/
+func apiNamesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	q := r.URL.Query()
+	count, seed := 10, newSeed()
+	if v := strings.TrimSpace(q.Get("count")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("count", fmt.Errorf("invalid count %q", v))))
+			return
+		}
+		count = n
+	}
+	if v := strings.TrimSpace(q.Get("seed")); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("seed", fmt.Errorf("invalid seed %q", v))))
+			return
+		}
+		seed = n
+	}
+	if count > 100 {
+		writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("count", fmt.Errorf("at most 100 names per request; %d asked", count))))
+		return
+	}
+	names, err := randomNames(newRoller(seed), q.Get("race"), q.Get("gender"), count)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(err))
+		return
+	}
+	gender, _ := normalizeGender(q.Get("gender"))
+	race := trimLower(q.Get("race"))
+	if race == "" {
+		race = defaultNameRace
+	}
+	writeJSON(w, http.StatusOK, namesResponse{Race: race, Gender: gender, Seed: seed, Names: names})
+}
+
+/**
+*  apiAwardHandler handles POST /api/characters/award (one character, or xp split over chars)
+**/
+func apiAwardHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req awardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: "invalid json"})
+		return
+	}
+	names := req.Chars
+	if strings.TrimSpace(req.Name) != "" {
+		names = append(names, req.Name)
+	}
+	targets := make([]*Character, 0, len(names))
+	copies := make([]Character, 0, len(names))
+	for _, n := range names {
+		c := findCharLike(strings.TrimSpace(n))
+		if c == nil {
+			writeJSON(w, http.StatusNotFound, apiError{Error: "character not found: " + n})
+			return
+		}
+		targets = append(targets, c)
+		copies = append(copies, *c)
+	}
+	updated, awards, err := awardXPSplit(copies, req.XP, req.LevelUp, req.HPMethod)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("xp", err)))
+		return
+	}
+	for i := range targets {
+		*targets[i] = updated[i]
+	}
+	saveCharacters()
+	writeJSON(w, http.StatusOK, awards)
+}
+
+/**
+*  apiSubclassHandler handles POST /api/characters/subclass
+**/
+func apiSubclassHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req subclassRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: "invalid json"})
+		return
+	}
+	c := findCharLike(strings.TrimSpace(req.Name))
+	if c == nil {
+		writeJSON(w, http.StatusNotFound, apiError{Error: "character not found"})
+		return
+	}
+	updated := *c
+	if _, err := chooseSubclass(&updated, req.Class, req.Subclass); err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("subclass", err)))
+		return
+	}
+	*c = updated
+	saveCharacters()
+	writeJSON(w, http.StatusOK, newCharacterResponse(c))
+}
+
+/**
+*  persona converts the request fields to the domain type (height in inches)
+**/
+func (p personaFields) persona() Persona {
+	return Persona{
+		Alignment:         p.Alignment,
+		PersonalityTraits: p.PersonalityTraits,
+		Ideal:             p.Ideal,
+		Bond:              p.Bond,
+		Flaw:              p.Flaw,
+		Age:               p.Age,
+		Height:            p.Height,
+		Weight:            p.Weight,
+		Eyes:              p.Eyes,
+		Hair:              p.Hair,
+	}
+}
+
+/**
+*  apiPersonaHandler handles POST /api/characters/persona (set by hand and/or roll personality and appearance)
+**/
+func apiPersonaHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req personaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: "invalid json"})
+		return
+	}
+	c := findCharLike(strings.TrimSpace(req.Name))
+	if c == nil {
+		writeJSON(w, http.StatusNotFound, apiError{Error: "character not found"})
+		return
+	}
+	seed := req.Seed
+	if seed == 0 {
+		seed = newSeed()
+	}
+	updated := *c
+	if err := applyPersona(&updated, req.persona(), req.Roll, seed); err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(err))
+		return
+	}
+	*c = updated
+	saveCharacters()
+	writeJSON(w, http.StatusOK, newCharacterResponse(c))
+}
+
+/**
+*  apiProficienciesHandler handles POST /api/characters/proficiencies; a list left out keeps the current picks
+**/
+func apiProficienciesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req proficienciesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: "invalid json"})
+		return
+	}
+	c := findCharLike(strings.TrimSpace(req.Name))
+	if c == nil {
+		writeJSON(w, http.StatusNotFound, apiError{Error: "character not found"})
+		return
+	}
+	if req.Languages == nil {
+		req.Languages = freePicks(kindLanguage, languageGrants(c), c.Languages)
+	}
+	if req.Tools == nil {
+		req.Tools = freePicks(kindTool, toolGrants(c), c.Tools)
+	}
+	updated := *c
+	if err := assignProficiencies(&updated, req.Languages, req.Tools, nil); err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(err))
+		return
+	}
+	*c = updated
+	saveCharacters()
+	writeJSON(w, http.StatusOK, newCharacterResponse(c))
+}
+
+/**
+*  newInventoryResponse lists a character's items with the carried weight
+**/
+func newInventoryResponse(c *Character, variant bool) inventoryResponse {
+	items := c.Inventory
+	if items == nil {
+		items = []Item{}
+	}
+	return inventoryResponse{Name: c.Name, Items: items, Encumbrance: encumbranceFor(c, variant)}
+}
+
+/**
+*  apiInventoryHandler handles GET /api/characters/inventory?name=X&variant=true and POST (add an item)
+**/
+func apiInventoryHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		c := findCharLike(strings.TrimSpace(r.URL.Query().Get("name")))
+		if c == nil {
+			writeJSON(w, http.StatusNotFound, apiError{Error: "character not found"})
+			return
+		}
+		variant, _ := strconv.ParseBool(r.URL.Query().Get("variant"))
+		writeJSON(w, http.StatusOK, newInventoryResponse(c, variant))
+	case http.MethodPost:
+		var req inventoryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, apiError{Error: "invalid json"})
+			return
+		}
+		c := findCharLike(strings.TrimSpace(req.Name))
+		if c == nil {
+			writeJSON(w, http.StatusNotFound, apiError{Error: "character not found"})
+			return
+		}
+		item := Item{Name: req.Item, Quantity: req.Quantity, Weight: -1, Notes: req.Notes, Equipped: req.Equipped, Attuned: req.Attuned}
+		if item.Quantity == 0 {
+			item.Quantity = 1
+		}
+		if req.Weight != nil {
+			if *req.Weight < 0 {
+				writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("weight", fmt.Errorf("weight must not be negative"))))
+				return
+			}
+			item.Weight = *req.Weight
+		}
+		updated := *c
+		updated.Inventory = append([]Item(nil), c.Inventory...)
+		if _, err := carryItem(&updated, item); err != nil {
+			writeJSON(w, http.StatusBadRequest, newAPIError(err))
+			return
+		}
+		*c = updated
+		saveCharacters()
+		writeJSON(w, http.StatusOK, newInventoryResponse(c, false))
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+/**
+*  apiInventoryRemoveHandler handles POST /api/characters/inventory/remove (quantity 0 removes the whole stack)
+**/
+func apiInventoryRemoveHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req inventoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: "invalid json"})
+		return
+	}
+	c := findCharLike(strings.TrimSpace(req.Name))
+	if c == nil {
+		writeJSON(w, http.StatusNotFound, apiError{Error: "character not found"})
+		return
+	}
+	updated := *c
+	updated.Inventory = append([]Item(nil), c.Inventory...)
+	if _, err := removeItem(&updated, req.Item, req.Quantity); err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(err))
+		return
+	}
+	*c = updated
+	saveCharacters()
+	writeJSON(w, http.StatusOK, newInventoryResponse(c, false))
+}
+
+/**
+*  apiTradeHandler handles POST /api/characters/buy and /api/characters/sell at SRD prices
+**/
+func apiTradeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req tradeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: "invalid json"})
+		return
+	}
+	c := findCharLike(strings.TrimSpace(req.Name))
+	if c == nil {
+		writeJSON(w, http.StatusNotFound, apiError{Error: "character not found"})
+		return
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+	trade := buyItem
+	if strings.HasSuffix(r.URL.Path, "/sell") {
+		trade = sellItem
+	}
+	updated := *c
+	updated.Inventory = append([]Item(nil), c.Inventory...)
+	if _, err := trade(&updated, req.Item, req.Quantity); err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(err))
+		return
+	}
+	*c = updated
+	saveCharacters()
+	writeJSON(w, http.StatusOK, newCharacterResponse(c))
+}
+
+/**
+*  apiCoinsHandler handles POST /api/characters/coins (add coins and/or spend an amount with change)
+**/
+func apiCoinsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req coinsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: "invalid json"})
+		return
+	}
+	c := findCharLike(strings.TrimSpace(req.Name))
+	if c == nil {
+		writeJSON(w, http.StatusNotFound, apiError{Error: "character not found"})
+		return
+	}
+	purse := c.Purse
+	if strings.TrimSpace(req.Add) != "" {
+		in, err := parseCoins(req.Add)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("add", err)))
+			return
+		}
+		purse = addCoins(purse, in)
+	}
+	if strings.TrimSpace(req.Spend) != "" {
+		cp, err := parseCost(req.Spend)
+		if err == nil {
+			purse, err = spendCoins(purse, cp)
+		}
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("spend", err)))
+			return
+		}
+	}
+	c.Purse = purse
+	saveCharacters()
+	writeJSON(w, http.StatusOK, newCharacterResponse(c))
+}
+
+/**
+*  apiEquipHandler handles POST /api/characters/equip; occupied slots need "replace": true
+**/
+func apiEquipHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req equipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: "invalid json"})
+		return
+	}
+	c := findCharLike(strings.TrimSpace(req.Name))
+	if c == nil {
+		writeJSON(w, http.StatusNotFound, apiError{Error: "character not found"})
+		return
+	}
+	updated := *c
+	updated.Inventory = append([]Item(nil), c.Inventory...)
+	var displaced []string
+	changed := false
+	for _, eq := range []struct{ slot, item string }{
+		{slotMain, req.Weapon},
+		{slotOff, req.OffHand},
+		{slotArmor, req.Armor},
+		{slotShield, req.Shield},
+	} {
+		if strings.TrimSpace(eq.item) == "" {
+			continue
+		}
+		out, err := equipSlot(&updated, eq.slot, eq.item, req.Replace)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, newAPIError(err))
+			return
+		}
+		displaced = append(displaced, out...)
+		changed = true
+	}
+	if !changed {
+		writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("weapon", fmt.Errorf("nothing to equip (give weapon, offhand, armor or shield)"))))
+		return
+	}
+	enrichEquipmentFrom(&updated, LocalEquipProv)
+	*c = updated
+	saveCharacters()
+	writeJSON(w, http.StatusOK, equipResponse{characterResponse: newCharacterResponse(c), Displaced: displaced, Warnings: handWarnings(c.Equipment)})
+}
+
+/**
+*  apiUnequipHandler handles POST /api/characters/unequip; the item stays in the inventory
+**/
+func apiUnequipHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req slotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: "invalid json"})
+		return
+	}
+	c := findCharLike(strings.TrimSpace(req.Name))
+	if c == nil {
+		writeJSON(w, http.StatusNotFound, apiError{Error: "character not found"})
+		return
+	}
+	slot, err := parseSlot(req.Slot)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(fieldError("slot", err)))
+		return
+	}
+	updated := *c
+	updated.Inventory = append([]Item(nil), c.Inventory...)
+	old, err := unequipSlot(&updated, slot)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(err))
+		return
+	}
+	*c = updated
+	saveCharacters()
+	writeJSON(w, http.StatusOK, equipResponse{characterResponse: newCharacterResponse(c), Displaced: []string{old}})
+}
+
+/**
+*  apiSwapHandsHandler handles POST /api/characters/swap-hands
+**/
+func apiSwapHandsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req slotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: "invalid json"})
+		return
+	}
+	c := findCharLike(strings.TrimSpace(req.Name))
+	if c == nil {
+		writeJSON(w, http.StatusNotFound, apiError{Error: "character not found"})
+		return
+	}
+	updated := *c
+	if err := swapHands(&updated); err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(err))
+		return
+	}
+	enrichEquipmentFrom(&updated, LocalEquipProv)
+	*c = updated
+	saveCharacters()
+	writeJSON(w, http.StatusOK, equipResponse{characterResponse: newCharacterResponse(c), Warnings: handWarnings(c.Equipment)})
+}
+
+/**
+*  apiImproveHandler handles POST /api/characters/improve (ability score improvements and feats)
+**/
+func apiImproveHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req improveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: "invalid json"})
+		return
+	}
+	c := findCharLike(strings.TrimSpace(req.Name))
+	if c == nil {
+		writeJSON(w, http.StatusNotFound, apiError{Error: "character not found"})
+		return
+	}
+	updated := *c
+	if _, err := applyImprovement(&updated, req.Class, req.Level, req.ASI, req.Feat); err != nil {
+		writeJSON(w, http.StatusBadRequest, newAPIError(fieldError(improvementField(req.Feat), err)))
+		return
+	}
+	*c = updated
+	saveCharacters()
+	writeJSON(w, http.StatusOK, newCharacterResponse(c))
 }
 
 /**
@@ -107,9 +1002,9 @@ func apiCharactersHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 
-func baseScoresFromReq(req createRequest) (AbilityScores, bool) {
+func baseScoresFromReq(req createRequest, priority []string) (AbilityScores, bool) {
 	if req.AbilityScores == nil {
-		return assignStandardArray(), false
+		return assignStandardArray(priority), false
 	}
 	s := *req.AbilityScores
 	providedAll := s.Strength > 0 && s.Dexterity > 0 && s.Constitution > 0 &&
@@ -132,29 +1027,67 @@ func baseScoresFromReq(req createRequest) (AbilityScores, bool) {
 }
 
 
-func applyRaceBonusesTo(base AbilityScores, race string) AbilityScores {
-	rStr, rDex, rCon, rInt, rWis, rCha := raceBonusDeltas(race)
-	return AbilityScores{
-		Strength:     base.Strength + rStr,
-		Dexterity:    base.Dexterity + rDex,
-		Constitution: base.Constitution + rCon,
-		Intelligence: base.Intelligence + rInt,
-		Wisdom:       base.Wisdom + rWis,
-		Charisma:     base.Charisma + rCha,
+/**
+*  scoresFromReq builds the final ability scores for the request's method
+**/
+func scoresFromReq(req createRequest, bonus AbilityScores, priority []string) (AbilityScores, *AbilityRoll, error) {
+	m, err := normalizeAbilityMethod(req.Method)
+	if err != nil {
+		return AbilityScores{}, nil, err
+	}
+	switch m {
+	case "pointbuy":
+		var s AbilityScores
+		if req.AbilityScores != nil {
+			s = *req.AbilityScores
+		}
+		base, _, err := pointBuyScores(s)
+		if err != nil {
+			return AbilityScores{}, nil, err
+		}
+		return applyRaceBonusesTo(base, bonus), nil, nil
+	case "roll":
+		seed := req.Seed
+		if seed == 0 {
+			seed = newSeed()
+		}
+		base, roll, err := rollAbilityScores(seed, req.Assign, priority)
+		if err != nil {
+			return AbilityScores{}, nil, err
+		}
+		return applyRaceBonusesTo(base, bonus), roll, nil
+	case "standard":
+		return applyRaceBonusesTo(assignStandardArray(priority), bonus), nil, nil
+	default:
+		base, providedAny := baseScoresFromReq(req, priority)
+		if providedAny {
+			return base, nil, nil
+		}
+		return applyRaceBonusesTo(base, bonus), nil, nil
 	}
 }
 
-func deriveSkillsFor(req createRequest, bg string) []string {
-	if len(req.Skills) == 0 {
-		return finalSkills(req.Class, bg, nil)
+/**
+*  pointBuyLeftFor reports the unspent points of a point-buy request
+**/
+func pointBuyLeftFor(req createRequest) (int, bool) {
+	if m, _ := normalizeAbilityMethod(req.Method); m != "pointbuy" {
+		return 0, false
 	}
-	var skills []string
-	for _, s := range req.Skills {
-		if t := strings.TrimSpace(s); t != "" {
-			skills = append(skills, normalizeSkill(t))
-		}
+	var s AbilityScores
+	if req.AbilityScores != nil {
+		s = *req.AbilityScores
 	}
-	return skills
+	_, left, err := pointBuyScores(s)
+	return left, err == nil
+}
+
+func applyRaceBonusesTo(base AbilityScores, bonus AbilityScores) AbilityScores {
+	return addScores(base, bonus)
+}
+
+func deriveSkillsFor(req createRequest, bg string) ([]string, error) {
+	return finalSkills(req.Class, bg, req.Skills)
 }
 
 func buildSpellcastingFor(class string, level int) *Spellcasting {
@@ -171,34 +1104,63 @@ func buildSpellcastingFor(class string, level int) *Spellcasting {
 /**
 *  buildCharacterFromRequest constructs a Character struct from an API request
 **/
-func buildCharacterFromRequest(req createRequest) Character {
-	base, providedAny := baseScoresFromReq(req)
-	final := base
-	if !providedAny {
-		final = applyRaceBonusesTo(base, req.Race)
+func buildCharacterFromRequest(req createRequest) (Character, error) {
+	classInfo, err := resolveClass(req.Class)
+	if err != nil {
+		return Character{}, fieldError("class", err)
+	}
+	priority, err := resolvePriority(req.Priority, req.Class)
+	if err != nil {
+		return Character{}, fieldError("priority", err)
+	}
+	raceInfo, err := resolveRace(req.Race)
+	if err != nil {
+		return Character{}, fieldError("race", err)
+	}
+	bonus, raceChoices, err := raceBonusesFor(raceInfo, req.RaceBonus, priority)
+	if err != nil {
+		return Character{}, fieldError("race_bonus", err)
+	}
+	final, roll, err := scoresFromReq(req, bonus, priority)
+	if err != nil {
+		return Character{}, fieldError("ability_scores", err)
 	}
 
-	bg := "acolyte"
-	skills := deriveSkillsFor(req, bg)
+	background, err := resolveBackground(req.Background)
+	if err != nil {
+		return Character{}, fieldError("background", err)
+	}
+	bg := trimLower(background.Name)
+	skills, err := deriveSkillsFor(req, bg)
+	if err != nil {
+		return Character{}, err
+	}
+	equipment := Equipment{
+		Armor:   strings.TrimSpace(req.Armor),
+		Weapon:  strings.TrimSpace(req.Weapon),
+		Shield:  strings.TrimSpace(req.Shield),
+		OffHand: strings.TrimSpace(req.OffHand),
+	}
+	if err := checkHands(equipment); err != nil {
+		return Character{}, err
+	}
 	sc := buildSpellcastingFor(req.Class, req.Level)
 
 	return Character{
-		Name:             req.Name,
-		Race:             strings.ToLower(strings.TrimSpace(req.Race)),
-		Class:            strings.ToLower(strings.TrimSpace(req.Class)),
-		Level:            req.Level,
-		Background:       bg,
-		AbilityScores:    final,
-		ProficiencyBonus: profByLevel(req.Level),
-		Skills:           skills,
-		Spellcasting:     sc,
-		Equipment: Equipment{
-			Armor:   strings.TrimSpace(req.Armor),
-			Weapon:  strings.TrimSpace(req.Weapon),
-			Shield:  strings.TrimSpace(req.Shield),
-			OffHand: strings.TrimSpace(req.OffHand),
-		},
-	}
+		Name:              req.Name,
+		Race:              normalizeRaceName(raceInfo.Name),
+		RaceChoices:       raceChoices,
+		Class:             trimLower(classInfo.Name),
+		Level:             req.Level,
+		Background:        bg,
+		BackgroundFeature: background.Feature,
+		AbilityScores:     final,
+		ProficiencyBonus:  profByLevel(req.Level),
+		Skills:            skills,
+		Spellcasting:      sc,
+		AbilityRoll:       roll,
+		Equipment:         equipment,
+	}, nil
 }
 
 /**
@@ -207,6 +1169,21 @@ func buildCharacterFromRequest(req createRequest) Character {
 func startServer(addr string) error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/characters", apiCharactersHandler)
+	mux.HandleFunc("/api/characters/levelup", apiLevelUpHandler)
+	mux.HandleFunc("/api/characters/improve", apiImproveHandler)
+	mux.HandleFunc("/api/characters/subclass", apiSubclassHandler)
+	mux.HandleFunc("/api/characters/award", apiAwardHandler)
+	mux.HandleFunc("/api/names", apiNamesHandler)
+	mux.HandleFunc("/api/characters/persona", apiPersonaHandler)
+	mux.HandleFunc("/api/characters/proficiencies", apiProficienciesHandler)
+	mux.HandleFunc("/api/characters/inventory", apiInventoryHandler)
+	mux.HandleFunc("/api/characters/inventory/remove", apiInventoryRemoveHandler)
+	mux.HandleFunc("/api/characters/buy", apiTradeHandler)
+	mux.HandleFunc("/api/characters/sell", apiTradeHandler)
+	mux.HandleFunc("/api/characters/coins", apiCoinsHandler)
+	mux.HandleFunc("/api/characters/equip", apiEquipHandler)
+	mux.HandleFunc("/api/characters/unequip", apiUnequipHandler)
+	mux.HandleFunc("/api/characters/swap-hands", apiSwapHandsHandler)
 
 	fileServer := http.FileServer(http.Dir("."))
 	mux.Handle("/", fileServer)
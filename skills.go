@@ -3,79 +3,261 @@
 package main
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 )
 
-var classSkillOptions = map[string][]string{
-	"barbarian": {"Animal Handling", "Athletics", "Intimidation", "Nature", "Perception", "Survival"},
-	"fighter":   {"Acrobatics", "Animal Handling", "Athletics", "History", "Insight", "Intimidation", "Perception", "Survival"},
-	"cleric":    {"History", "Insight", "Medicine", "Persuasion", "Religion"},
-	"wizard":    {"Arcana", "History", "Insight", "Investigation", "Medicine", "Religion"},
-	"rogue":     {"Acrobatics", "Athletics", "Deception", "Insight", "Intimidation", "Investigation", "Perception", "Performance", "Persuasion", "Sleight of Hand", "Stealth"},
-	"ranger":    {"Animal Handling", "Athletics", "Insight", "Investigation", "Nature", "Perception", "Stealth", "Survival"},
-	"paladin":   {"Athletics", "Insight", "Intimidation", "Medicine", "Persuasion", "Religion"},
-	"warlock":   {"Arcana", "Deception", "History", "Intimidation", "Investigation", "Nature", "Religion"},
-	"monk":      {"Acrobatics", "Athletics", "History", "Insight", "Religion", "Stealth"},
+var allSkills = []string{
+	"acrobatics", "animal handling", "arcana", "athletics", "deception", "history",
+	"insight", "intimidation", "investigation", "medicine", "nature", "perception",
+	"performance", "persuasion", "religion", "sleight of hand", "stealth", "survival",
 }
 
-var classSkillCount = map[string]int{
-	"barbarian": 2, "fighter": 2, "cleric": 2, "wizard": 2,
-	"rogue": 4, "ranger": 3, "paladin": 2, "warlock": 2, "monk": 2,
+var skillAbilities = map[string]string{
+	"athletics":       "str",
+	"acrobatics":      "dex",
+	"sleight of hand": "dex",
+	"stealth":         "dex",
+	"arcana":          "int",
+	"history":         "int",
+	"investigation":   "int",
+	"nature":          "int",
+	"religion":        "int",
+	"animal handling": "wis",
+	"insight":         "wis",
+	"medicine":        "wis",
+	"perception":      "wis",
+	"survival":        "wis",
+	"deception":       "cha",
+	"intimidation":    "cha",
+	"performance":     "cha",
+	"persuasion":      "cha",
 }
 
-var backgroundSkills = map[string][]string{
-    "acolyte": {"Insight", "Religion"},
+/**
+*  classSkillOptions returns the skills a class may choose from
+**/
+func classSkillOptions(className string) []string {
+	c, _ := lookupClass(className)
+	return append([]string(nil), c.Skills...)
 }
 
 /**
-*  defaultClassSkills returns default skill proficiencies for a class
+*  classSkillCount returns how many class skills a class picks
 **/
-func defaultClassSkills(className string) []string {
-	low := strings.ToLower(className)
-	opts := append([]string(nil), classSkillOptions[low]...) 
-	sort.Strings(opts)
-	n := classSkillCount[low]
-	if n <= 0 { n = 2 }
-	if len(opts) > n { opts = opts[:n] }
-	return opts
+func classSkillCount(className string) int {
+	c, _ := lookupClass(className)
+	return c.SkillCount
 }
 
 /**
-*  defaultBackgroundSkills returns skills granted by a background
+*  defaultBackgroundSkills returns skills granted by a background from the catalog
 **/
 func defaultBackgroundSkills(bg string) []string {
-	return append([]string{}, backgroundSkills[strings.ToLower(bg)]...)
+	b, _ := lookupBackground(bg)
+	return append([]string{}, b.Skills...)
 }
 
 /**
-*  normalizeSkill lowercases, trims for a skill name and replaces underscores with spaces
+*  normalizeSkill lowercases, trims for a skill name and replaces underscores and hyphens with spaces
 **/
 func normalizeSkill(s string) string {
-	return strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), "_", " "))), " ")
+	s = strings.NewReplacer("_", " ", "-", " ").Replace(strings.TrimSpace(s))
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
 }
 
 /**
-*  finalSkills returns the final normalized, sorted skills from provided/class/bg
+*  containsString reports whether list holds s
 **/
-func finalSkills(className, bg string, provided []string) []string {
-	var out []string
-	if len(provided) > 0 {
-		for _, p := range provided {
-			if t := strings.TrimSpace(p); t != "" {
-				out = append(out, normalizeSkill(t))
-			}
-		}
-	} else {
-		for _, s := range defaultClassSkills(className) {
-			out = append(out, normalizeSkill(s))
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
 		}
 	}
+	return false
+}
+
+/**
+*  finalSkills validates the class picks and returns the normalized, sorted skills from provided/class/bg.
+*  Picks that repeat a background skill do not count; when that leaves too few, each repeat is reported
+*  by name so another skill can be picked. When a class skill overlaps the background, the SRD lets the
+*  player take any other skill instead.
+**/
+func finalSkills(className, bg string, provided []string) ([]string, error) {
+	bgSkills := make([]string, 0, 2)
 	if bg != "" {
 		for _, s := range defaultBackgroundSkills(bg) {
-			out = append(out, normalizeSkill(s))
+			bgSkills = append(bgSkills, normalizeSkill(s))
+		}
+	}
+	options := make([]string, 0)
+	for _, s := range classSkillOptions(className) {
+		options = append(options, normalizeSkill(s))
+	}
+	count := classSkillCount(className)
+	_, known := lookupClass(className)
+
+	ve := &ValidationError{}
+	var picks []string
+	type repeat struct{ field, skill string }
+	var repeats []repeat
+	for i, p := range provided {
+		n := normalizeSkill(p)
+		field := fmt.Sprintf("skills[%d]", i)
+		switch {
+		case n == "":
+			continue
+		case skillAbilities[n] == "":
+			ve.add(field, "%s", unknownNameError("skill", strings.TrimSpace(p), allSkills))
+		case containsString(picks, n):
+			ve.add(field, "%s is listed twice", n)
+		case containsString(bgSkills, n):
+			repeats = append(repeats, repeat{field, n})
+		default:
+			picks = append(picks, n)
+		}
+	}
+	if err := ve.errOrNil(); err != nil {
+		return nil, err
+	}
+	if len(picks) == 0 && len(repeats) == 0 {
+		picks = defaultSkillPicks(options, bgSkills, count)
+	}
+
+	overlap := 0
+	for _, s := range bgSkills {
+		if containsString(options, s) {
+			overlap++
+		}
+	}
+	outside := 0
+	for _, p := range picks {
+		if !containsString(options, p) {
+			outside++
+		}
+	}
+	switch {
+	case !known:
+		// no class to check against; keep the picks as given
+	case count == 0 && len(picks) > 0:
+		ve.add("skills", "class %q has no skill choices", className)
+	case len(picks) > count:
+		ve.add("skills", "%s picks %d skills from its list; %d given", className, count, len(picks))
+	case len(picks) < count && len(repeats) > 0:
+		for _, r := range repeats {
+			ve.add(r.field, "%s is already granted by the %s background; pick another skill in its place", r.skill, bg)
+		}
+	case len(picks) < count:
+		ve.add("skills", "%s picks %d skills from its list; only %d given", className, count, len(picks))
+	case outside > overlap:
+		for _, p := range picks {
+			if !containsString(options, p) {
+				ve.add("skills", "%s is not a %s skill (choose from %s)", p, className, strings.Join(options, ", "))
+				break
+			}
+		}
+	}
+	if err := ve.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	out := append(picks, bgSkills...)
+	sort.Strings(out)
+	return out, nil
+}
+
+/**
+*  defaultSkillPicks takes the first class options alphabetically, skipping background skills
+**/
+func defaultSkillPicks(options, bgSkills []string, count int) []string {
+	sorted := append([]string(nil), options...)
+	sort.Strings(sorted)
+	var out []string
+	for _, s := range sorted {
+		if len(out) < count && !containsString(bgSkills, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+/**
+*  hasExpertise reports whether the character doubles proficiency for the skill
+**/
+func hasExpertise(c *Character, name string) bool {
+	return containsString(c.Expertise, normalizeSkill(name))
+}
+
+/**
+*  expertiseSlots returns how many expertise picks the class grants at level
+**/
+func expertiseSlots(className string, level int) int {
+	ci, _ := lookupClass(className)
+	return levelTableValue(ci.Expertise, level)
+}
+
+/**
+*  expertiseSlotsFor adds up the expertise picks of all the character's classes
+**/
+func expertiseSlotsFor(c *Character) int {
+	total := 0
+	for _, cl := range classLevels(c) {
+		total += expertiseSlots(cl.Class, cl.Level)
+	}
+	return total
+}
+
+/**
+*  validateExpertise checks expertise picks are known, proficient and within the class allowance
+**/
+func validateExpertise(c *Character, picks []string) ([]string, error) {
+	var out []string
+	for _, p := range picks {
+		n := normalizeSkill(p)
+		if _, ok := skillAbilities[n]; !ok {
+			return nil, unknownNameError("skill", p, allSkills)
+		}
+		if !hasSkill(c, n) {
+			return nil, fmt.Errorf("expertise in %s needs proficiency in it first", n)
 		}
+		if !containsString(out, n) {
+			out = append(out, n)
+		}
+	}
+	if limit := expertiseSlotsFor(c); len(out) > limit {
+		return nil, fmt.Errorf("%s can have %d expertise skills; %d given", formatClassLevels(c), limit, len(out))
 	}
 	sort.Strings(out)
+	return out, nil
+}
+
+/**
+*  skillModifier returns ability mod + proficiency (doubled with expertise) for a skill
+**/
+func skillModifier(c *Character, name string) SkillModifier {
+	n := normalizeSkill(name)
+	ab := skillAbilities[n]
+	sm := SkillModifier{Skill: n, Ability: ab, Modifier: abilityMod(abilityScoreByName(c, ab))}
+	if hasSkill(c, n) {
+		sm.Proficient = true
+		sm.Modifier += c.ProficiencyBonus
+		if hasExpertise(c, n) {
+			sm.Expertise = true
+			sm.Modifier += c.ProficiencyBonus
+		}
+	}
+	return sm
+}
+
+/**
+*  computeSkillTable returns modifiers for all 18 SRD skills in alphabetical order
+**/
+func computeSkillTable(c *Character) []SkillModifier {
+	out := make([]SkillModifier, 0, len(allSkills))
+	for _, s := range allSkills {
+		out = append(out, skillModifier(c, s))
+	}
 	return out
-}
\ No newline at end of file
+}
@@ -11,16 +11,10 @@ import (
 *  casterType returns "full", "half", "warlock", or "none" for a class name
 **/
 func casterType(class string) string {
-	switch strings.ToLower(strings.TrimSpace(class)) {
-	case "wizard", "cleric", "druid", "bard", "sorcerer":
-		return "full"
-	case "paladin", "ranger":
-		return "half"
-	case "warlock":
-		return "warlock"
-	default:
-		return "none"
+	if c, ok := lookupClass(class); ok {
+		return c.Caster
 	}
+	return "none"
 }
 
 
@@ -21,6 +21,12 @@ func loadCharacters() {
 		return
 	}
 	_ = json.Unmarshal(data, &characters)
+	for i := range characters {
+		ensureHP(&characters[i])
+		ensureBaseScores(&characters[i])
+		ensureProficiencies(&characters[i])
+		ensureEquipmentInInventory(&characters[i])
+	}
 }
 
 /**
@@ -0,0 +1,117 @@
+// Layer: Domain (business rules: subclass choice, features and always-prepared spells; no IO)
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/**
+*  subclassLevel returns the class level at which a subclass is chosen (0 when the class has none)
+**/
+func subclassLevel(class string) int {
+	lvl := 0
+	for _, s := range csvSubclasses[trimLower(class)] {
+		if lvl == 0 || s.Level < lvl {
+			lvl = s.Level
+		}
+	}
+	return lvl
+}
+
+/**
+*  subclassOf returns the subclass chosen for one of the character's classes
+**/
+func subclassOf(c *Character, class string) string {
+	return c.Subclasses[trimLower(class)]
+}
+
+/**
+*  chooseSubclass records a subclass for class (default: the first class) and adds its always-prepared spells
+**/
+func chooseSubclass(c *Character, class, name string) (Subclass, error) {
+	if strings.TrimSpace(class) == "" {
+		class = c.Class
+	}
+	class = trimLower(class)
+	level := classLevelOf(c, class)
+	if level == 0 {
+		return Subclass{}, fmt.Errorf("%s has no levels in %s", c.Name, class)
+	}
+	sc, ok := lookupSubclass(class, name)
+	if !ok {
+		return Subclass{}, unknownNameError(class+" subclass", strings.TrimSpace(name), subclassNames(class))
+	}
+	if level < sc.Level {
+		return Subclass{}, fmt.Errorf("%s chooses a subclass at level %d; %s is level %d", class, sc.Level, c.Name, level)
+	}
+	if cur := subclassOf(c, class); cur != "" && !strings.EqualFold(cur, sc.Name) {
+		return Subclass{}, fmt.Errorf("%s already follows %s", c.Name, cur)
+	}
+	subs := make(map[string]string, len(c.Subclasses)+1)
+	for k, v := range c.Subclasses {
+		subs[k] = v
+	}
+	subs[class] = sc.Name
+	c.Subclasses = subs
+	refreshSpellcasting(c)
+	return sc, nil
+}
+
+/**
+*  syncSubclassSpells adds domain/oath spells unlocked by class level as always prepared
+**/
+func syncSubclassSpells(c *Character) {
+	if c.Spellcasting == nil {
+		return
+	}
+	for _, cl := range classLevels(c) {
+		sc, ok := lookupSubclass(cl.Class, subclassOf(c, cl.Class))
+		if !ok {
+			continue
+		}
+		levels := make([]int, 0, len(sc.Spells))
+		for l := range sc.Spells {
+			levels = append(levels, l)
+		}
+		sort.Ints(levels)
+		for _, l := range levels {
+			if l > cl.Level {
+				continue
+			}
+			for _, name := range sc.Spells[l] {
+				markAlwaysPrepared(c, trimLower(name))
+			}
+		}
+	}
+}
+
+/**
+*  markAlwaysPrepared flags a known spell as always prepared, adding it to the list when missing
+**/
+func markAlwaysPrepared(c *Character, name string) {
+	for i := range c.Spellcasting.Spells {
+		if trimLower(c.Spellcasting.Spells[i].Name) == name {
+			c.Spellcasting.Spells[i].Prepared = true
+			c.Spellcasting.Spells[i].AlwaysPrepared = true
+			return
+		}
+	}
+	lvl, _ := spellLevelByName(name)
+	c.Spellcasting.Spells = append(c.Spellcasting.Spells, Spell{Name: name, Level: lvl, Prepared: true, AlwaysPrepared: true})
+}
+
+/**
+*  pendingSubclassChoices lists classes that reached their subclass level without choosing one
+**/
+func pendingSubclassChoices(c *Character) []string {
+	var out []string
+	for _, cl := range classLevels(c) {
+		if lvl := subclassLevel(cl.Class); lvl > 0 && cl.Level >= lvl && subclassOf(c, cl.Class) == "" {
+			out = append(out, fmt.Sprintf("choose a %s subclass (%s)", cl.Class, strings.Join(subclassNames(cl.Class), ", ")))
+		}
+	}
+	return out
+}
@@ -0,0 +1,131 @@
+// Layer: Infrastructure (data source adapter: load the subclass catalog from CSV)
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultSubclassesFile = "5e-SRD-Subclasses.csv"
+
+var (
+	csvSubclasses       = map[string][]Subclass{}
+	subclassesCSVLoaded = false
+)
+
+/**
+*  tryLoadSubclasses attempts to load the subclasses CSV from the first path that works
+**/
+func tryLoadSubclasses(paths ...string) bool {
+	for _, p := range paths {
+		if err := loadSubclassesFromCSV(p); err == nil {
+			subclassesCSVLoaded = true
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	if p := strings.TrimSpace(os.Getenv("SUBCLASSES_CSV")); p != "" && tryLoadSubclasses(p) {
+		return
+	}
+	if tryLoadSubclasses(
+		defaultSubclassesFile,
+		filepath.Join("data", defaultSubclassesFile),
+		filepath.Join("Data", defaultSubclassesFile),
+		filepath.Join("DATA", defaultSubclassesFile),
+	) {
+		return
+	}
+	if exe, err := os.Executable(); err == nil {
+		dir := filepath.Dir(exe)
+		_ = tryLoadSubclasses(
+			filepath.Join(dir, defaultSubclassesFile),
+			filepath.Join(dir, "data", defaultSubclassesFile),
+		)
+	}
+}
+
+/**
+*  parseLevelNames parses "1:Bless;1:Cure Wounds;3:Spiritual Weapon" into level -> names
+**/
+func parseLevelNames(cell string) map[int][]string {
+	out := map[int][]string{}
+	for _, part := range splitList(cell) {
+		lv, name, ok := strings.Cut(part, ":")
+		l, err := strconv.Atoi(strings.TrimSpace(lv))
+		if ok && err == nil && strings.TrimSpace(name) != "" {
+			out[l] = append(out[l], strings.TrimSpace(name))
+		}
+	}
+	return out
+}
+
+/**
+*  loadSubclassesFromCSV parses the subclasses CSV into per-class lists
+**/
+func loadSubclassesFromCSV(path string) error {
+	rows, err := readCatalogCSV(path)
+	if err != nil {
+		return err
+	}
+	hdr := rows[0]
+	iClass := findColumnIndex(hdr, "class")
+	iName := findColumnIndex(hdr, "name")
+	iLevel := findColumnIndex(hdr, "level")
+	if iClass < 0 || iName < 0 || iLevel < 0 {
+		return errors.New("subclasses CSV missing required headers: class, name, level")
+	}
+	iSpells := findColumnIndex(hdr, "spells")
+	iDesc := findColumnIndex(hdr, "description")
+
+	tmp := map[string][]Subclass{}
+	for _, row := range rows[1:] {
+		class := trimLower(cellAt(row, iClass))
+		name := cellAt(row, iName)
+		if class == "" || name == "" {
+			continue
+		}
+		tmp[class] = append(tmp[class], Subclass{
+			Class:       class,
+			Name:        name,
+			Level:       atoiCell(row, iLevel),
+			Spells:      parseLevelNames(cellAt(row, iSpells)),
+			Description: cellAt(row, iDesc),
+		})
+	}
+	for k := range tmp {
+		sort.Slice(tmp[k], func(i, j int) bool { return tmp[k][i].Name < tmp[k][j].Name })
+	}
+	csvSubclasses = tmp
+	return nil
+}
+
+/**
+*  lookupSubclass returns the catalog entry for a class's subclass
+**/
+func lookupSubclass(class, name string) (Subclass, bool) {
+	for _, s := range csvSubclasses[trimLower(class)] {
+		if trimLower(s.Name) == trimLower(name) {
+			return s, true
+		}
+	}
+	return Subclass{}, false
+}
+
+/**
+*  subclassNames returns the lowercase subclass names of a class
+**/
+func subclassNames(class string) []string {
+	out := make([]string, 0, len(csvSubclasses[trimLower(class)]))
+	for _, s := range csvSubclasses[trimLower(class)] {
+		out = append(out, trimLower(s.Name))
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+// Layer: Infrastructure (data source adapter: load the tool catalog from CSV)
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultToolsFile = "5e-SRD-Tools.csv"
+
+var (
+	csvTools       = []Tool{}
+	toolsCSVLoaded = false
+)
+
+/**
+*  tryLoadTools attempts to load the tools CSV from the first path that works
+**/
+func tryLoadTools(paths ...string) bool {
+	for _, p := range paths {
+		if err := loadToolsFromCSV(p); err == nil {
+			toolsCSVLoaded = true
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	if p := strings.TrimSpace(os.Getenv("TOOLS_CSV")); p != "" && tryLoadTools(p) {
+		return
+	}
+	if tryLoadTools(
+		defaultToolsFile,
+		filepath.Join("data", defaultToolsFile),
+		filepath.Join("Data", defaultToolsFile),
+		filepath.Join("DATA", defaultToolsFile),
+	) {
+		return
+	}
+	if exe, err := os.Executable(); err == nil {
+		dir := filepath.Dir(exe)
+		_ = tryLoadTools(
+			filepath.Join(dir, defaultToolsFile),
+			filepath.Join(dir, "data", defaultToolsFile),
+		)
+	}
+}
+
+/**
+*  loadToolsFromCSV parses the tools CSV (artisan's tools, kits, gaming sets, instruments, vehicles)
+**/
+func loadToolsFromCSV(path string) error {
+	rows, err := readCatalogCSV(path)
+	if err != nil {
+		return err
+	}
+	hdr := rows[0]
+	iName := findColumnIndex(hdr, "name")
+	iCategory := findColumnIndex(hdr, "category")
+	if iName < 0 || iCategory < 0 {
+		return errors.New("tools CSV missing required headers: name, category")
+	}
+
+	var tmp []Tool
+	for _, row := range rows[1:] {
+		if name := cellAt(row, iName); name != "" {
+			tmp = append(tmp, Tool{Name: name, Category: trimLower(cellAt(row, iCategory))})
+		}
+	}
+	csvTools = tmp
+	return nil
+}
+
+/**
+*  lookupTool returns the catalog entry for a tool name
+**/
+func lookupTool(name string) (Tool, bool) {
+	for _, t := range csvTools {
+		if trimLower(t.Name) == trimLower(name) {
+			return t, true
+		}
+	}
+	return Tool{}, false
+}
+
+/**
+*  toolNames returns the lowercase tool names in catalog order
+**/
+func toolNames() []string {
+	out := make([]string, 0, len(csvTools))
+	for _, t := range csvTools {
+		out = append(out, trimLower(t.Name))
+	}
+	return out
+}
@@ -14,16 +14,247 @@ type AbilityScores struct {
 }
 
 type Character struct {
-	Name             string
-	Race             string
-	Class            string
-	Level            int
-	AbilityScores    AbilityScores
-	Background       string
+	Name              string
+	Race              string
+	RaceChoices       []string
+	Class             string
+	Classes           []ClassLevel
+	Subclasses        map[string]string
+	Level             int
+	XP                int
+	AbilityScores     AbilityScores
+	BaseAbilityScores AbilityScores
+	Improvements      []Improvement
+	Background        string
+	BackgroundFeature string
+	Persona
+	PersonaSeed      int64
 	ProficiencyBonus int
+	MaxHP            int
+	CurrentHP        int
+	TempHP           int
+	HPMethod         string
+	HPSeed           int64
+	HitDieRolls      []int
 	Equipment        Equipment
 	Skills           []string
+	Expertise        []string
+	Languages        []string
+	Tools            []string
+	Inventory        []Item
+	Purse            Purse
 	Spellcasting     *Spellcasting
+	AbilityRoll      *AbilityRoll
+}
+
+type Persona struct {
+	Alignment         string
+	PersonalityTraits []string
+	Ideal             string
+	Bond              string
+	Flaw              string
+	Age               int
+	Height            int
+	Weight            int
+	Eyes              string
+	Hair              string
+}
+
+type TraitEntry struct {
+	Text      string
+	Alignment string
+}
+
+type ClassLevel struct {
+	Class string
+	Level int
+}
+
+type Race struct {
+	Name          string
+	Parent        string
+	Bonuses       AbilityScores
+	Size          string
+	Speed         int
+	Darkvision    int
+	Languages     []string
+	Traits        []string
+	ChoiceCount   int
+	ChoiceAmount  int
+	ChoiceExclude []string
+	AgeAdult      int
+	AgeMax        int
+	HeightBase    int
+	HeightMod     string
+	WeightBase    int
+	WeightMod     string
+	Tools         []string
+	Eyes          []string
+	Hair          []string
+}
+
+type Language struct {
+	Name     string
+	Type     string
+	Script   string
+	Speakers []string
+}
+
+type Tool struct {
+	Name     string
+	Category string
+}
+
+type ClassInfo struct {
+	Name         string
+	HitDie       int
+	Primary      string
+	Priority     []string
+	Saves        []string
+	Armor        []string
+	Weapons      []string
+	Tools        []string
+	SkillCount   int
+	Skills       []string
+	Caster       string
+	SpellAbility string
+	Learns       bool
+	Prepares     bool
+	Cantrips     map[int]int
+	Expertise    map[int]int
+	Multiclass   []string
+	StartingGold string
+	SpellsKnown  map[int]int
+}
+
+type Improvement struct {
+	Class     string
+	Level     int
+	Increases AbilityScores
+	Feat      string
+}
+
+type Feat struct {
+	Name          string
+	Prerequisites []string
+	Increase      []string
+	Description   string
+}
+
+type LevelUpChoices struct {
+	Class     string
+	Spells    []string
+	Expertise []string
+	ASI       []string
+	Feat      string
+	Subclass  string
+}
+
+type NameList struct {
+	Race   string
+	Given  map[string][]string
+	Family []string
+}
+
+type GenerateOptions struct {
+	Seed       int64
+	Name       string
+	Class      string
+	Race       string
+	Background string
+	Gender     string
+	Level      int
+}
+
+type XPAward struct {
+	Name       string
+	Gained     int
+	XP         int
+	Level      int
+	ReadyLevel int
+	LevelUp    *LevelUpResult
+}
+
+type ClassFeature struct {
+	Class    string
+	Subclass string
+	Level    int
+	Name     string
+	Values   []FeatureValue
+}
+
+type FeatureValue struct {
+	Label string
+	Value string
+}
+
+type FeatureScaling struct {
+	Class   string
+	Feature string
+	Label   string
+	Values  map[int]string
+}
+
+type Subclass struct {
+	Class       string
+	Name        string
+	Level       int
+	Spells      map[int][]string
+	Description string
+}
+
+type LevelUpResult struct {
+	Class            string
+	ClassLevel       int
+	From             int
+	To               int
+	HPGained         int
+	ProficiencyBonus int
+	Features         []ClassFeature
+	Upgraded         []ClassFeature
+	Improvement      *Improvement
+	NewSpells        []string
+	Choices          []string
+}
+
+type SavingThrow struct {
+	Ability    string
+	Modifier   int
+	Proficient bool
+}
+
+type SkillModifier struct {
+	Skill      string
+	Ability    string
+	Modifier   int
+	Proficient bool
+	Expertise  bool
+}
+
+type DerivedStats struct {
+	ArmorClass        int
+	Initiative        int
+	PassivePerception int
+	SavingThrows      []SavingThrow
+	Skills            []SkillModifier
+}
+
+type AbilityRoll struct {
+	Seed   int64
+	Dice   [][]int
+	Totals []int
+	Assign string
+}
+
+type Background struct {
+	Name        string
+	Skills      []string
+	Tools       []string
+	Languages   []string
+	Equipment   []string
+	Gold        int
+	Feature     string
+	FeatureDesc string
 }
 
 type Equipment struct {
@@ -36,12 +267,49 @@ type Equipment struct {
 	ArmorInfo  ArmorMeta
 }
 
-type Spell struct {
+type Item struct {
 	Name     string
-	Level    int
-	School   string
-	Range    string
-	Prepared bool
+	Quantity int
+	Weight   float64
+	Notes    string
+	Equipped bool
+	Attuned  bool
+}
+
+type StartingKit struct {
+	Class   string
+	Choices []EquipmentChoice
+	Fixed   []string
+}
+
+type EquipmentChoice struct {
+	Options [][]string
+}
+
+type Purse struct {
+	CP int
+	SP int
+	EP int
+	GP int
+	PP int
+}
+
+type Encumbrance struct {
+	Carried      float64
+	Capacity     float64
+	PushDragLift float64
+	Variant      bool
+	Status       string
+	Speed        int
+}
+
+type Spell struct {
+	Name           string
+	Level          int
+	School         string
+	Range          string
+	Prepared       bool
+	AlwaysPrepared bool
 }
 
 type WeaponMeta struct {
@@ -61,5 +329,6 @@ type ArmorMeta struct {
 
 type Spellcasting struct {
 	SlotsByLevel map[int]int
+	PactSlots    map[int]int
 	Spells       []Spell
 }
\ No newline at end of file
@@ -2,7 +2,10 @@
 
 package main
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 
 /**
@@ -35,55 +38,10 @@ func abilityMod(score int) int {
 }
 
 /**
-*   assignStandardArray returns the SRD standard array assigned in order
+*   assignStandardArray returns the SRD standard array assigned by ability priority
 **/
-func assignStandardArray() AbilityScores {
-	arr := StandardArray
-	return AbilityScores{
-		Strength:     arr[0],
-		Dexterity:    arr[1],
-		Constitution: arr[2],
-		Intelligence: arr[3],
-		Wisdom:       arr[4],
-		Charisma:     arr[5],
-	}
-}
-
-/**
-*  raceBonusDeltas gives the stat bonuses for a race
-**/
-func raceBonusDeltas(race string) (str, dex, con, intl, wis, cha int) {
-	r := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(race), "-", " "))
-
-	switch {
-	case strings.Contains(r, "lightfoot halfling"):
-		return 0, 2, 0, 0, 0, 1
-	case strings.Contains(r, "stout halfling"):
-		return 0, 2, 1, 0, 0, 0
-	case strings.Contains(r, "halfling"):
-		return 0, 2, 0, 0, 0, 0
-	}
-
-	switch r {
-	case "human":
-		return 1, 1, 1, 1, 1, 1
-	case "hill dwarf":
-		return 0, 0, 2, 0, 1, 0
-	case "dwarf":
-		return 0, 0, 2, 0, 0, 0
-	case "elf":
-		return 0, 2, 0, 0, 0, 0
-	case "dragonborn":
-		return 2, 0, 0, 0, 0, 1
-	case "gnome":
-		return 0, 0, 0, 2, 0, 0
-	case "half orc":
-		return 2, 0, 1, 0, 0, 0
-	case "tiefling":
-		return 0, 0, 0, 1, 0, 2
-	default:
-		return 0, 0, 0, 0, 0, 0
-	}
+func assignStandardArray(priority []string) AbilityScores {
+	return assignByPriority(StandardArray, priority)
 }
 
 /**
@@ -103,6 +61,61 @@ func slugify(s string) string {
 	return s
 }
 
+/**
+*  editDistance returns the Levenshtein distance between a and b
+**/
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+/**
+*  suggestName returns the option closest to s, or "" when none is close enough
+**/
+func suggestName(s string, options []string) string {
+	q := strings.ToLower(strings.TrimSpace(s))
+	best, bestDist := "", -1
+	for _, o := range options {
+		lo := strings.ToLower(o)
+		if len(q) >= 3 && (strings.Contains(lo, q) || strings.HasPrefix(q, lo)) {
+			return o
+		}
+		if d := editDistance(q, lo); bestDist < 0 || d < bestDist {
+			best, bestDist = o, d
+		}
+	}
+	if bestDist < 0 || bestDist > max(2, len(q)/3) {
+		return ""
+	}
+	return best
+}
+
+/**
+*  unknownNameError builds an "unknown <kind>" error with a suggestion when one is close
+**/
+func unknownNameError(kind, value string, options []string) error {
+	if s := suggestName(value, options); s != "" {
+		return fmt.Errorf("unknown %s %q (did you mean %q?)", kind, value, s)
+	}
+	return fmt.Errorf("unknown %s %q (known: %s)", kind, value, strings.Join(options, ", "))
+}
+
 /**
 *  abilityScoreByName returns a character's score by short name (str/dex/...)
 **/
@@ -0,0 +1,57 @@
+// Layer: Domain (business rules: field-level validation errors; no IO)
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (v *ValidationError) Error() string {
+	parts := make([]string, 0, len(v.Fields))
+	for _, f := range v.Fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+/**
+*  add records a problem with one input field
+**/
+func (v *ValidationError) add(field, format string, args ...any) {
+	v.Fields = append(v.Fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+/**
+*  errOrNil returns v as an error only when it holds at least one field problem
+**/
+func (v *ValidationError) errOrNil() error {
+	if len(v.Fields) == 0 {
+		return nil
+	}
+	return v
+}
+
+/**
+*  fieldError tags a plain error with the input field it came from
+**/
+func fieldError(field string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return err
+	}
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: err.Error()}}}
+}
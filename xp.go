@@ -0,0 +1,102 @@
+// Layer: Domain (business rules: experience points and level thresholds; no IO)
+
+package main
+
+import "fmt"
+
+// xpThresholds[level] is the SRD experience needed to reach that level
+var xpThresholds = [maxCharacterLevel + 1]int{
+	0, 0, 300, 900, 2700, 6500, 14000, 23000, 34000, 48000, 64000,
+	85000, 100000, 120000, 140000, 165000, 195000, 225000, 265000, 305000, 355000,
+}
+
+/**
+*  levelForXP returns the highest level the experience total qualifies for
+**/
+func levelForXP(xp int) int {
+	lvl := 1
+	for l := 2; l <= maxCharacterLevel; l++ {
+		if xp >= xpThresholds[l] {
+			lvl = l
+		}
+	}
+	return lvl
+}
+
+/**
+*  xpForNextLevel returns the XP total needed for the level after level (0 at the cap)
+**/
+func xpForNextLevel(level int) int {
+	if level >= maxCharacterLevel {
+		return 0
+	}
+	return xpThresholds[max(level, 0)+1]
+}
+
+/**
+*  splitXP divides total evenly over n characters; the remainder goes to the first ones
+**/
+func splitXP(total, n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = total / n
+		if i < total%n {
+			out[i]++
+		}
+	}
+	return out
+}
+
+/**
+*  awardXP adds experience to c and reports the level the new total allows.
+*  With autoLevel, c is raised to that level (HP by hpMethod) and the level-up result is attached.
+**/
+func awardXP(c *Character, xp int, autoLevel bool, hpMethod string) (XPAward, error) {
+	if xp <= 0 {
+		return XPAward{}, fmt.Errorf("xp must be positive; %d given", xp)
+	}
+	c.XP += xp
+	award := XPAward{Name: c.Name, Gained: xp, XP: c.XP, Level: c.Level, ReadyLevel: max(levelForXP(c.XP), c.Level)}
+	if autoLevel && award.ReadyLevel > c.Level {
+		updated, res, err := levelUp(*c, award.ReadyLevel, hpMethod, 0, LevelUpChoices{})
+		if err != nil {
+			return award, err
+		}
+		*c = updated
+		award.Level = c.Level
+		award.LevelUp = &res
+	}
+	return award, nil
+}
+
+/**
+*  awardXPSplit splits xp evenly over copies of the characters; nothing is returned on error so callers can keep the originals
+**/
+func awardXPSplit(chars []Character, xp int, autoLevel bool, hpMethod string) ([]Character, []XPAward, error) {
+	if len(chars) == 0 {
+		return nil, nil, fmt.Errorf("no characters to award xp to")
+	}
+	if xp <= 0 {
+		return nil, nil, fmt.Errorf("xp must be positive; %d given", xp)
+	}
+	if xp < len(chars) {
+		return nil, nil, fmt.Errorf("%d xp can't be split over %d characters", xp, len(chars))
+	}
+	seen := map[string]bool{}
+	for _, c := range chars {
+		if seen[trimLower(c.Name)] {
+			return nil, nil, fmt.Errorf("%s is listed twice", c.Name)
+		}
+		seen[trimLower(c.Name)] = true
+	}
+	out := append([]Character(nil), chars...)
+	awards := make([]XPAward, 0, len(chars))
+	for i, share := range splitXP(xp, len(chars)) {
+		a, err := awardXP(&out[i], share, autoLevel, hpMethod)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", out[i].Name, err)
+		}
+		awards = append(awards, a)
+	}
+	return out, awards, nil
+}